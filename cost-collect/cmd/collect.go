@@ -7,16 +7,16 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"syscall"
 	"time"
 
-	"cost-collect/pkg/config"
 	"cost-collect/pkg/monitor"
+	"costconfig/config"
 	"github.com/spf13/cobra"
 )
 
-var configPath string
 var interval int
 
 // getPidFilePath는 PID 파일의 경로를 반환합니다.
@@ -24,14 +24,27 @@ func getPidFilePath(cfg *config.Config) string {
 	return filepath.Join(cfg.Storage.DataDir, "cost-collect.pid")
 }
 
+// loadConfigWithSecrets loads the config and resolves the credentials from
+// environment variables, helper commands and files
+func loadConfigWithSecrets() (*config.Config, error) {
+	cfg, err := config.LoadConfig(env.ConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("설정 로딩 실패: %w", err)
+	}
+	if err := cfg.ResolveSecrets(); err != nil {
+		return nil, fmt.Errorf("인증 정보 확인 실패: %w", err)
+	}
+	return cfg, nil
+}
+
 var collectCmd = &cobra.Command{
 	Use:   "start",
 	Short: "데이터 수집 시작",
-	Long:  `NHN Cloud 인스턴스 상태 데이터 수집을 시작합니다. 백그라운드 실행을 원하시면 'start &' 형태로 실행하세요.`,
+	Long:  `설정된 CSP(NHN Cloud, AWS, Naver Cloud)의 인스턴스 상태 데이터 수집을 시작합니다. 백그라운드 실행을 원하시면 'start &' 형태로 실행하세요.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.LoadConfig(configPath)
+		cfg, err := loadConfigWithSecrets()
 		if err != nil {
-			return fmt.Errorf("설정 로딩 실패: %w", err)
+			return err
 		}
 
 		pidFilePath := getPidFilePath(cfg)
@@ -46,13 +59,16 @@ var collectCmd = &cobra.Command{
 			}
 		}
 
-		if cfg.NHNCloud.TenantID == "" || cfg.NHNCloud.Username == "" || cfg.NHNCloud.Password == "" {
-			return fmt.Errorf("NHN Cloud 인증 정보가 설정되지 않았습니다. 설정 파일을 확인해주세요")
+		if !cfg.NHNCloud.Configured() && !cfg.AWS.Configured() && !cfg.NaverCloud.Configured() {
+			return fmt.Errorf("CSP 인증 정보(NHN Cloud, AWS, Naver Cloud)가 설정되지 않았습니다. 설정 파일을 확인해주세요")
 		}
 
 		if interval > 0 {
 			cfg.Monitor.IntervalMinutes = interval
 		}
+		if cfg.Monitor.IntervalMinutes == 0 {
+			cfg.Monitor.IntervalMinutes = config.DefaultIntervalMinutes
+		}
 
 		// PID 파일 작성
 		pid := os.Getpid()
@@ -96,7 +112,7 @@ var stopCmd = &cobra.Command{
 	Short: "데이터 수집 중지",
 	Long:  `실행 중인 데이터 수집기 프로세스를 중지합니다.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.LoadConfig(configPath)
+		cfg, err := config.LoadConfig(env.ConfigPath)
 		if err != nil {
 			return fmt.Errorf("설정 로딩 실패: %w", err)
 		}
@@ -148,13 +164,13 @@ var onceCmd = &cobra.Command{
 	Short: "일회성 데이터 수집",
 	Long:  `인스턴스 상태를 한 번만 수집합니다.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.LoadConfig(configPath)
+		cfg, err := loadConfigWithSecrets()
 		if err != nil {
-			return fmt.Errorf("설정 로딩 실패: %w", err)
+			return err
 		}
 
-		if cfg.NHNCloud.TenantID == "" || cfg.NHNCloud.Username == "" || cfg.NHNCloud.Password == "" {
-			return fmt.Errorf("NHN Cloud 인증 정보가 설정되지 않았습니다")
+		if !cfg.NHNCloud.Configured() && !cfg.AWS.Configured() && !cfg.NaverCloud.Configured() {
+			return fmt.Errorf("CSP 인증 정보(NHN Cloud, AWS, Naver Cloud)가 설정되지 않았습니다")
 		}
 
 		m := monitor.NewMonitor(cfg)
@@ -171,7 +187,8 @@ var onceCmd = &cobra.Command{
 
 		fmt.Printf("데이터 수집이 완료되었습니다. (소요시간: %v)\n", elapsed)
 		fmt.Printf("수집된 인스턴스: %d개\n", stats.TotalInstances)
-		fmt.Printf("실행 중: %d개, 정지: %d개\n", stats.RunningInstances, stats.ShutdownInstances)
+		fmt.Printf("실행 중: %d개, 정지: %d개, 삭제: %d개\n", stats.RunningInstances, stats.ShutdownInstances, stats.DeletedInstances)
+		printStatusCounts(stats.StatusCounts)
 
 		return nil
 	},
@@ -182,7 +199,7 @@ var statusCmd = &cobra.Command{
 	Short: "수집기 상태 확인",
 	Long:  `데이터 수집기의 현재 상태를 확인합니다.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.LoadConfig(configPath)
+		cfg, err := config.LoadConfig(env.ConfigPath)
 		if err != nil {
 			return fmt.Errorf("설정 로딩 실패: %w", err)
 		}
@@ -201,19 +218,32 @@ var statusCmd = &cobra.Command{
 		fmt.Printf("총 인스턴스: %d개\n", stats.TotalInstances)
 		fmt.Printf("실행 중: %d개\n", stats.RunningInstances)
 		fmt.Printf("정지 상태: %d개\n", stats.ShutdownInstances)
+		fmt.Printf("삭제됨: %d개\n", stats.DeletedInstances)
+		printStatusCounts(stats.StatusCounts)
 		fmt.Printf("설정된 수집 간격: %d분\n", cfg.Monitor.IntervalMinutes)
 
 		return nil
 	},
 }
 
+// printStatusCounts prints the number of instances in each OpenStack status
+func printStatusCounts(counts map[string]int) {
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	for _, status := range statuses {
+		fmt.Printf("  - %s: %d개\n", status, counts[status])
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(collectCmd)
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(onceCmd)
 	rootCmd.AddCommand(statusCmd)
 
-	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "설정 파일 경로")
-
 	collectCmd.Flags().IntVarP(&interval, "interval", "i", 0, "수집 간격 (분, 0이면 설정파일 값 사용)")
 }
\ No newline at end of file
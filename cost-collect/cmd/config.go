@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
-	"cost-collect/pkg/config"
+	"costconfig/config"
+	"costconfig/configcmd"
 )
 
 var configCmd = &cobra.Command{
@@ -19,18 +22,41 @@ var configShowCmd = &cobra.Command{
 	Short: "현재 설정 표시",
 	Long:  `현재 설정을 표시합니다.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.LoadConfig(configPath)
+		cfg, err := config.LoadConfig(env.ConfigPath)
 		if err != nil {
 			return fmt.Errorf("설정 로딩 실패: %w", err)
 		}
 
 		fmt.Println("=== 현재 설정 ===")
+		if env.ActiveProfile != "" {
+			fmt.Printf("프로필: %s (%s)\n", env.ActiveProfile, env.ConfigPath)
+		} else {
+			fmt.Printf("설정 파일: %s\n", env.ConfigPath)
+		}
 		fmt.Printf("NHN Cloud:\n")
-		fmt.Printf("  - Tenant ID: %s\n", cfg.NHNCloud.TenantID)
-		fmt.Printf("  - Username: %s\n", cfg.NHNCloud.Username)
-		fmt.Printf("  - Password: %s\n", maskPassword(cfg.NHNCloud.Password))
+		fmt.Printf("  - Tenant ID: %s\n", configcmd.DescribeValue(cfg.NHNCloud.TenantID, config.EnvNHNTenantID))
+		fmt.Printf("  - Username: %s\n", configcmd.DescribeValue(cfg.NHNCloud.Username, config.EnvNHNUsername))
+		fmt.Printf("  - Password: %s\n", configcmd.DescribeSecret(cfg.NHNCloud.Password, config.EnvNHNPassword, cfg.NHNCloud.PasswordFile, cfg.NHNCloud.PasswordCommand))
 		fmt.Printf("  - Region: %s\n", cfg.NHNCloud.Region)
 		fmt.Printf("  - Identity URL: %s\n", cfg.NHNCloud.IdentityURL)
+		if cfg.AWS.AccessKeyID != "" || os.Getenv(config.EnvAWSAccessKeyID) != "" {
+			fmt.Printf("\nAWS:\n")
+			fmt.Printf("  - Access Key ID: %s\n", configcmd.DescribeValue(cfg.AWS.AccessKeyID, config.EnvAWSAccessKeyID))
+			fmt.Printf("  - Secret Access Key: %s\n", configcmd.DescribeSecret(cfg.AWS.SecretAccessKey, config.EnvAWSSecretAccessKey, cfg.AWS.SecretAccessKeyFile, cfg.AWS.SecretAccessKeyCommand))
+			fmt.Printf("  - Regions: %s\n", strings.Join(cfg.AWS.Regions, ", "))
+			if cfg.AWS.Endpoint != "" {
+				fmt.Printf("  - Endpoint: %s\n", cfg.AWS.Endpoint)
+			}
+		}
+		if cfg.NaverCloud.AccessKey != "" || os.Getenv(config.EnvNaverAccessKey) != "" {
+			fmt.Printf("\nNaver Cloud:\n")
+			fmt.Printf("  - Access Key: %s\n", configcmd.DescribeValue(cfg.NaverCloud.AccessKey, config.EnvNaverAccessKey))
+			fmt.Printf("  - Secret Key: %s\n", configcmd.DescribeSecret(cfg.NaverCloud.SecretKey, config.EnvNaverSecretKey, cfg.NaverCloud.SecretKeyFile, cfg.NaverCloud.SecretKeyCommand))
+			fmt.Printf("  - Regions: %s\n", strings.Join(cfg.NaverCloud.Regions, ", "))
+			if cfg.NaverCloud.Endpoint != "" {
+				fmt.Printf("  - Endpoint: %s\n", cfg.NaverCloud.Endpoint)
+			}
+		}
 		fmt.Printf("\n모니터링:\n")
 		fmt.Printf("  - 간격: %d분\n", cfg.Monitor.IntervalMinutes)
 		fmt.Printf("  - 자동 시작: %t\n", cfg.Monitor.AutoStart)
@@ -43,19 +69,10 @@ var configShowCmd = &cobra.Command{
 	},
 }
 
-
-func maskPassword(password string) string {
-	if password == "" {
-		return "(설정되지 않음)"
-	}
-	if len(password) <= 4 {
-		return "****"
-	}
-	return password[:2] + "****" + password[len(password)-2:]
-}
-
 func init() {
+	configCmd.AddCommand(env.InitCommand())
 	configCmd.AddCommand(configShowCmd)
-	
+	configCmd.AddCommand(env.Commands()...)
+
 	rootCmd.AddCommand(configCmd)
-}
\ No newline at end of file
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"cost-collect/pkg/monitor"
+)
+
+var flavorsCmd = &cobra.Command{
+	Use:   "flavors",
+	Short: "flavor 정보 관리",
+	Long:  `Nova API의 flavor 정보를 동기화합니다.`,
+}
+
+var flavorsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "flavor 정보 동기화",
+	Long: `Nova API(/flavors/detail)에서 리전의 flavor 이름과 사양(vCPU, 메모리, 디스크)을 가져와 저장하고,
+수집된 인스턴스가 사용 중이지만 가격 파일에 없는 flavor를 보고합니다.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfigWithSecrets()
+		if err != nil {
+			return err
+		}
+
+		if !cfg.NHNCloud.Configured() {
+			return fmt.Errorf("NHN Cloud 인증 정보가 설정되지 않았습니다")
+		}
+
+		m := monitor.NewMonitor(cfg)
+		result, err := m.SyncFlavors()
+		if err != nil {
+			return err
+		}
+
+		region := result.Region
+		if region == "" {
+			region = "(설정되지 않음)"
+		}
+		fmt.Printf("flavor 동기화가 완료되었습니다. (리전: %s)\n", region)
+		fmt.Printf("전체 flavor: %d개, 새 flavor: %d개\n", result.Total, len(result.Added))
+		for _, id := range result.Added {
+			fmt.Printf("  + %s\n", id)
+		}
+
+		if len(result.Unpriced) == 0 {
+			fmt.Println("사용 중인 flavor는 모두 가격 정보가 있습니다.")
+			return nil
+		}
+
+		fmt.Printf("\n가격 정보가 없는 사용 중 flavor: %d개\n", len(result.Unpriced))
+		for _, flavor := range result.Unpriced {
+			fmt.Printf("  - %s\n", monitor.DescribeFlavor(flavor))
+			fmt.Printf("    인스턴스: %s\n", strings.Join(flavor.Instances, ", "))
+		}
+		fmt.Println("\n가격 파일에 위 flavor를 추가하지 않으면 costcli calculate에서 추정 가격으로 계산되거나 합계에서 제외됩니다.")
+
+		return nil
+	},
+}
+
+func init() {
+	flavorsCmd.AddCommand(flavorsSyncCmd)
+
+	rootCmd.AddCommand(flavorsCmd)
+}
@@ -0,0 +1,322 @@
+package aws
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"cost-collect/pkg/storage"
+	"costconfig/config"
+)
+
+// CSPName is the pricing file CSP that EC2 instances are priced under
+const CSPName = "aws"
+
+// EC2 Query API 버전
+const apiVersion = "2016-11-15"
+
+// DefaultRegion is collected when no region is configured
+const DefaultRegion = "us-east-1"
+
+// EC2 instance state names (instanceState.name)
+const (
+	StatePending      = "pending"
+	StateRunning      = "running"
+	StateShuttingDown = "shutting-down"
+	StateTerminated   = "terminated"
+	StateStopping     = "stopping"
+	StateStopped      = "stopped"
+)
+
+type Client struct {
+	config     *config.AWSConfig
+	httpClient *http.Client
+}
+
+type DescribeInstancesResponse struct {
+	Reservations []Reservation `xml:"reservationSet>item"`
+	NextToken    string        `xml:"nextToken"`
+}
+
+type Reservation struct {
+	Instances []EC2Instance `xml:"instancesSet>item"`
+}
+
+type EC2Instance struct {
+	InstanceID        string             `xml:"instanceId"`
+	InstanceType      string             `xml:"instanceType"`
+	LaunchTime        string             `xml:"launchTime"`
+	State             EC2State           `xml:"instanceState"`
+	Reason            string             `xml:"reason"` // stateTransitionReason
+	Tags              []EC2Tag           `xml:"tagSet>item"`
+	NetworkInterfaces []NetworkInterface `xml:"networkInterfaceSet>item"`
+}
+
+type EC2State struct {
+	Code int    `xml:"code"`
+	Name string `xml:"name"`
+}
+
+type EC2Tag struct {
+	Key   string `xml:"key"`
+	Value string `xml:"value"`
+}
+
+type NetworkInterface struct {
+	Attachment NetworkAttachment `xml:"attachment"`
+}
+
+type NetworkAttachment struct {
+	DeviceIndex int    `xml:"deviceIndex"`
+	AttachTime  string `xml:"attachTime"`
+}
+
+type ErrorResponse struct {
+	Errors []APIError `xml:"Errors>Error"`
+}
+
+type APIError struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// reasonTimePattern extracts the time from a state transition reason such as
+// "User initiated (2025-08-20 13:59:07 GMT)"
+var reasonTimePattern = regexp.MustCompile(`\((\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}) GMT\)`)
+
+func NewClient(cfg *config.AWSConfig) *Client {
+	return &Client{
+		config: cfg,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Name is the provider name shown in logs
+func (c *Client) Name() string {
+	return "AWS EC2"
+}
+
+// CSP returns the pricing file CSP of the collected instances
+func (c *Client) CSP() string {
+	return CSPName
+}
+
+// Regions returns the configured regions, or us-east-1 when none is set
+func (c *Client) Regions() []string {
+	if len(c.config.Regions) == 0 {
+		return []string{DefaultRegion}
+	}
+	return c.config.Regions
+}
+
+// GetInstances lists the instances of every configured region. Terminated
+// and shutting-down instances are left out so that they are recorded as
+// deleted.
+func (c *Client) GetInstances() ([]*storage.InstanceState, error) {
+	var instances []*storage.InstanceState
+	now := time.Now()
+
+	for _, region := range c.Regions() {
+		ec2Instances, err := c.describeInstances(region, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", region, err)
+		}
+
+		for _, ec2Instance := range ec2Instances {
+			status, powerState, listed := mapState(ec2Instance.State.Name)
+			if !listed {
+				continue
+			}
+
+			launchTime, err := time.Parse(time.RFC3339, ec2Instance.LaunchTime)
+			if err != nil {
+				launchTime = now
+			}
+
+			// launchTime은 정지 후 다시 시작하면 바뀌므로 기본 네트워크 인터페이스의 연결 시각을 생성 시각으로 사용
+			createdAt := launchTime
+			for _, networkInterface := range ec2Instance.NetworkInterfaces {
+				if networkInterface.Attachment.DeviceIndex != 0 {
+					continue
+				}
+				if attachTime, err := time.Parse(time.RFC3339, networkInterface.Attachment.AttachTime); err == nil && attachTime.Before(createdAt) {
+					createdAt = attachTime
+				}
+			}
+
+			// 마지막 상태 변경 시각: 정지 사유의 시각, 없으면 마지막 시작 시각
+			updatedAt := launchTime
+			if transitionAt, ok := reasonTime(ec2Instance.Reason); ok && transitionAt.After(updatedAt) {
+				updatedAt = transitionAt
+			}
+
+			name := ec2Instance.InstanceID
+			var metadata map[string]string
+			if len(ec2Instance.Tags) > 0 {
+				metadata = make(map[string]string, len(ec2Instance.Tags))
+				for _, tag := range ec2Instance.Tags {
+					metadata[tag.Key] = tag.Value
+					if tag.Key == "Name" && tag.Value != "" {
+						name = tag.Value
+					}
+				}
+			}
+
+			instances = append(instances, &storage.InstanceState{
+				ID:                ec2Instance.InstanceID,
+				Name:              name,
+				FlavorID:          ec2Instance.InstanceType,
+				Region:            region,
+				CSP:               CSPName,
+				CurrentStatus:     status,
+				CurrentPowerState: powerState,
+				CreatedAt:         createdAt,
+				LastUpdated:       updatedAt,
+				LastSeen:          now,
+				Metadata:          metadata,
+			})
+		}
+	}
+
+	return instances, nil
+}
+
+// GetDeletedInstances returns the termination time of instances that are
+// shutting down or terminated. EC2 lists terminated instances only for about
+// an hour, so since is not used to narrow the query.
+func (c *Client) GetDeletedInstances(since time.Time) (map[string]time.Time, error) {
+	filters := url.Values{}
+	filters.Set("Filter.1.Name", "instance-state-name")
+	filters.Set("Filter.1.Value.1", StateShuttingDown)
+	filters.Set("Filter.1.Value.2", StateTerminated)
+
+	deleted := make(map[string]time.Time)
+	for _, region := range c.Regions() {
+		ec2Instances, err := c.describeInstances(region, filters)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", region, err)
+		}
+		for _, ec2Instance := range ec2Instances {
+			if deletedAt, ok := reasonTime(ec2Instance.Reason); ok {
+				deleted[ec2Instance.InstanceID] = deletedAt
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// describeInstances calls DescribeInstances in a region and follows
+// nextToken until every page is read.
+func (c *Client) describeInstances(region string, filters url.Values) ([]EC2Instance, error) {
+	var instances []EC2Instance
+	nextToken := ""
+	for {
+		form := url.Values{}
+		form.Set("Action", "DescribeInstances")
+		form.Set("Version", apiVersion)
+		form.Set("MaxResults", "1000")
+		for key, values := range filters {
+			form[key] = values
+		}
+		if nextToken != "" {
+			form.Set("NextToken", nextToken)
+		}
+
+		var page DescribeInstancesResponse
+		if err := c.post(region, form, &page); err != nil {
+			return nil, err
+		}
+		for _, reservation := range page.Reservations {
+			instances = append(instances, reservation.Instances...)
+		}
+
+		if page.NextToken == "" {
+			return instances, nil
+		}
+		nextToken = page.NextToken
+	}
+}
+
+// post sends a signed EC2 Query API request and decodes the XML response
+func (c *Client) post(region string, form url.Values, out interface{}) error {
+	body := []byte(form.Encode())
+	req, err := http.NewRequest("POST", c.endpoint(region), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("요청 생성 실패: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signRequest(req, body, c.config.AccessKeyID, c.config.SecretAccessKey, c.config.SessionToken, region, "ec2", time.Now())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("인스턴스 목록 요청 실패: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("응답 읽기 실패: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp ErrorResponse
+		if xml.Unmarshal(data, &errResp) == nil && len(errResp.Errors) > 0 {
+			return fmt.Errorf("인스턴스 목록 조회 실패 (상태코드: %d): %s: %s", resp.StatusCode, errResp.Errors[0].Code, errResp.Errors[0].Message)
+		}
+		return fmt.Errorf("인스턴스 목록 조회 실패 (상태코드: %d): %s", resp.StatusCode, string(data))
+	}
+
+	if err := xml.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("응답 파싱 실패: %w", err)
+	}
+
+	return nil
+}
+
+// endpoint returns the configured EC2-compatible endpoint or the region's
+// AWS endpoint
+func (c *Client) endpoint(region string) string {
+	if c.config.Endpoint != "" {
+		return strings.TrimRight(c.config.Endpoint, "/") + "/"
+	}
+	return fmt.Sprintf("https://ec2.%s.amazonaws.com/", region)
+}
+
+// mapState converts an EC2 state into the Nova status and power state used
+// by the instance storage. listed is false for instances that are going away.
+func mapState(state string) (status string, powerState int, listed bool) {
+	switch state {
+	case StatePending:
+		return storage.StatusBuild, storage.PowerStateNoState, true
+	case StateRunning:
+		return storage.StatusActive, storage.PowerStateRunning, true
+	case StateStopping, StateStopped:
+		// EC2는 정지 중인 인스턴스에 인스턴스 요금을 부과하지 않음
+		return storage.StatusShutoff, storage.PowerStateShutdown, true
+	case StateShuttingDown, StateTerminated:
+		return storage.StatusDeleted, storage.PowerStateNoState, false
+	}
+	return strings.ToUpper(state), storage.PowerStateNoState, true
+}
+
+// reasonTime parses the time in a state transition reason
+func reasonTime(reason string) (time.Time, bool) {
+	match := reasonTimePattern.FindStringSubmatch(reason)
+	if match == nil {
+		return time.Time{}, false
+	}
+	at, err := time.Parse("2006-01-02 15:04:05", match[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return at, true
+}
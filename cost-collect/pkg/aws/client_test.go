@@ -0,0 +1,170 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"cost-collect/pkg/storage"
+	"costconfig/config"
+)
+
+type stubInstance struct {
+	id, instanceType, state, reason string
+}
+
+// credentialPattern extracts the region from the SigV4 credential scope
+var credentialPattern = regexp.MustCompile(`Credential=[^/]+/\d{8}/([^/]+)/ec2/aws4_request`)
+
+// newEC2Stub serves DescribeInstances one instance per page, keyed by the
+// region in the request signature, and records the requests it received
+func newEC2Stub(t *testing.T, instances map[string][]stubInstance) (*httptest.Server, *[]string) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		if r.Method != "POST" || r.PostForm.Get("Action") != "DescribeInstances" || r.PostForm.Get("Version") != apiVersion {
+			t.Errorf("unexpected request %s %v", r.Method, r.PostForm)
+		}
+		match := credentialPattern.FindStringSubmatch(r.Header.Get("Authorization"))
+		if match == nil {
+			t.Errorf("missing SigV4 authorization: %q", r.Header.Get("Authorization"))
+			http.Error(w, "unsigned", http.StatusForbidden)
+			return
+		}
+		region := match[1]
+		calls = append(calls, region+" token="+r.PostForm.Get("NextToken"))
+
+		var states []string
+		if r.PostForm.Get("Filter.1.Name") == "instance-state-name" {
+			states = []string{r.PostForm.Get("Filter.1.Value.1"), r.PostForm.Get("Filter.1.Value.2")}
+		}
+		var items []stubInstance
+		for _, instance := range instances[region] {
+			if states == nil || instance.state == states[0] || instance.state == states[1] {
+				items = append(items, instance)
+			}
+		}
+
+		page, _ := strconv.Atoi(r.PostForm.Get("NextToken"))
+		var body strings.Builder
+		body.WriteString(`<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><reservationSet>`)
+		if page < len(items) {
+			item := items[page]
+			fmt.Fprintf(&body, `<item><instancesSet><item><instanceId>%s</instanceId><instanceType>%s</instanceType>`+
+				`<launchTime>2025-08-01T00:00:00.000Z</launchTime><instanceState><code>0</code><name>%s</name></instanceState>`+
+				`<reason>%s</reason><tagSet><item><key>Name</key><value>name-%s</value></item></tagSet></item></instancesSet></item>`,
+				item.id, item.instanceType, item.state, item.reason, item.id)
+		}
+		body.WriteString(`</reservationSet>`)
+		if page+1 < len(items) {
+			fmt.Fprintf(&body, `<nextToken>%d</nextToken>`, page+1)
+		}
+		body.WriteString(`</DescribeInstancesResponse>`)
+		w.Write([]byte(body.String()))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestGetInstancesPagesRegionsAndStates(t *testing.T) {
+	server, calls := newEC2Stub(t, map[string][]stubInstance{
+		"ap-northeast-2": {
+			{id: "i-running", instanceType: "t3.micro", state: StateRunning},
+			{id: "i-stopping", instanceType: "t3.small", state: StateStopping},
+			{id: "i-stopped", instanceType: "t3.small", state: StateStopped, reason: "User initiated (2025-08-20 13:59:07 GMT)"},
+			{id: "i-terminated", instanceType: "t3.micro", state: StateTerminated, reason: "User initiated (2025-08-21 00:00:00 GMT)"},
+		},
+		"us-east-1": {
+			{id: "i-pending", instanceType: "m5.large", state: StatePending},
+			{id: "i-shutting-down", instanceType: "m5.large", state: StateShuttingDown},
+		},
+	})
+
+	client := NewClient(&config.AWSConfig{
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		Regions:         []string{"ap-northeast-2", "us-east-1"},
+		Endpoint:        server.URL,
+	})
+
+	instances, err := client.GetInstances()
+	if err != nil {
+		t.Fatalf("GetInstances: %v", err)
+	}
+
+	// 페이지마다 nextToken을 따라가야 함 (지역별 인스턴스 수만큼 요청)
+	wantCalls := []string{
+		"ap-northeast-2 token=", "ap-northeast-2 token=1", "ap-northeast-2 token=2", "ap-northeast-2 token=3",
+		"us-east-1 token=", "us-east-1 token=1",
+	}
+	if strings.Join(*calls, ",") != strings.Join(wantCalls, ",") {
+		t.Errorf("requests = %v, want %v", *calls, wantCalls)
+	}
+
+	got := make(map[string]*storage.InstanceState)
+	for _, instance := range instances {
+		got[instance.ID] = instance
+	}
+	ids := make([]string, 0, len(got))
+	for id := range got {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if want := []string{"i-pending", "i-running", "i-stopped", "i-stopping"}; strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Fatalf("instances = %v, want %v (terminated and shutting-down excluded)", ids, want)
+	}
+
+	tests := []struct {
+		id, status, region string
+		powerState         int
+	}{
+		{"i-running", storage.StatusActive, "ap-northeast-2", storage.PowerStateRunning},
+		{"i-stopping", storage.StatusShutoff, "ap-northeast-2", storage.PowerStateShutdown},
+		{"i-stopped", storage.StatusShutoff, "ap-northeast-2", storage.PowerStateShutdown},
+		{"i-pending", storage.StatusBuild, "us-east-1", storage.PowerStateNoState},
+	}
+	for _, tt := range tests {
+		instance := got[tt.id]
+		if instance.CurrentStatus != tt.status || instance.CurrentPowerState != tt.powerState {
+			t.Errorf("%s: status %s/%d, want %s/%d", tt.id, instance.CurrentStatus, instance.CurrentPowerState, tt.status, tt.powerState)
+		}
+		if instance.Region != tt.region || instance.CSP != CSPName || instance.Name != "name-"+tt.id {
+			t.Errorf("%s: region %q csp %q name %q", tt.id, instance.Region, instance.CSP, instance.Name)
+		}
+	}
+
+	// 정지 사유의 시각이 마지막 상태 변경 시각
+	if want := time.Date(2025, 8, 20, 13, 59, 7, 0, time.UTC); !got["i-stopped"].LastUpdated.Equal(want) {
+		t.Errorf("i-stopped LastUpdated = %s, want %s", got["i-stopped"].LastUpdated, want)
+	}
+}
+
+func TestGetDeletedInstances(t *testing.T) {
+	server, _ := newEC2Stub(t, map[string][]stubInstance{
+		"us-east-1": {
+			{id: "i-running", instanceType: "t3.micro", state: StateRunning},
+			{id: "i-terminated", instanceType: "t3.micro", state: StateTerminated, reason: "User initiated (2025-08-21 09:30:00 GMT)"},
+		},
+	})
+
+	client := NewClient(&config.AWSConfig{AccessKeyID: "AKID", SecretAccessKey: "secret", Endpoint: server.URL})
+	deleted, err := client.GetDeletedInstances(time.Time{})
+	if err != nil {
+		t.Fatalf("GetDeletedInstances: %v", err)
+	}
+
+	if len(deleted) != 1 {
+		t.Fatalf("deleted = %v, want only i-terminated", deleted)
+	}
+	if want := time.Date(2025, 8, 21, 9, 30, 0, 0, time.UTC); !deleted["i-terminated"].Equal(want) {
+		t.Errorf("i-terminated deleted at %s, want %s", deleted["i-terminated"], want)
+	}
+}
@@ -0,0 +1,184 @@
+package monitor
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"cost-collect/pkg/nhncloud"
+	"cost-collect/pkg/storage"
+)
+
+// flavorSyncInterval is how often flavors are re-synced when no unknown
+// flavor shows up in between. Flavors that a sync could not find are retried
+// at this interval too.
+const flavorSyncInterval = 24 * time.Hour
+
+// FlavorSyncResult summarizes one flavor sync
+type FlavorSyncResult struct {
+	Region   string
+	Total    int
+	Added    []string
+	Unpriced []UnpricedFlavor
+}
+
+// UnpricedFlavor is a flavor used by live instances that has no price in the
+// pricing file. Spec is nil when the flavor is not in the synced catalog.
+type UnpricedFlavor struct {
+	FlavorID  string
+	Spec      *storage.FlavorSpec
+	Instances []string
+}
+
+// SyncFlavors fetches the region's flavors from Nova, saves them and reports
+// the flavors in use that have no price.
+func (m *Monitor) SyncFlavors() (*FlavorSyncResult, error) {
+	if m.nhnClient == nil {
+		return nil, fmt.Errorf("NHN Cloud 인증 정보가 설정되지 않았습니다")
+	}
+
+	flavors, err := m.nhnClient.GetFlavors()
+	if err != nil {
+		return nil, fmt.Errorf("flavor 목록 조회 실패: %w", err)
+	}
+
+	region := m.nhnClient.Region()
+	added := m.flavorStorage.UpdateRegion(region, flavors, time.Now())
+	if err := m.flavorStorage.SaveToFile(m.flavorFile()); err != nil {
+		return nil, fmt.Errorf("flavor 정보 저장 실패: %w", err)
+	}
+
+	unpriced, err := m.UnpricedFlavors()
+	if err != nil {
+		return nil, err
+	}
+
+	return &FlavorSyncResult{
+		Region:   region,
+		Total:    len(flavors),
+		Added:    added,
+		Unpriced: unpriced,
+	}, nil
+}
+
+// UnpricedFlavors returns the flavors of non-deleted instances that are
+// missing from the pricing file, sorted by flavor ID.
+func (m *Monitor) UnpricedFlavors() ([]UnpricedFlavor, error) {
+	pricing := storage.NewPricingStorage()
+	if err := pricing.LoadFromFile(m.config.Storage.PriceFile); err != nil {
+		return nil, fmt.Errorf("가격 정보 로딩 실패: %w", err)
+	}
+
+	byFlavor := make(map[string]*UnpricedFlavor)
+	for _, instance := range m.instanceStorage.GetAllInstances() {
+		if instance.DeletedAt != nil || instance.FlavorID == "" {
+			continue
+		}
+		if _, exists := pricing.GetCSPFlavorPrice(instance.CSP, instance.FlavorID); exists {
+			continue
+		}
+		flavor, exists := byFlavor[instance.FlavorID]
+		if !exists {
+			flavor = &UnpricedFlavor{FlavorID: instance.FlavorID}
+			flavor.Spec, _ = m.flavorStorage.GetFlavor(instance.Region, instance.FlavorID)
+			byFlavor[instance.FlavorID] = flavor
+		}
+		flavor.Instances = append(flavor.Instances, instance.Name)
+	}
+
+	unpriced := make([]UnpricedFlavor, 0, len(byFlavor))
+	for _, flavor := range byFlavor {
+		sort.Strings(flavor.Instances)
+		unpriced = append(unpriced, *flavor)
+	}
+	sort.Slice(unpriced, func(i, j int) bool {
+		return unpriced[i].FlavorID < unpriced[j].FlavorID
+	})
+	return unpriced, nil
+}
+
+// checkFlavors syncs flavors when the catalog is stale or an NHN Cloud
+// instance uses a flavor that is not in the catalog, and logs each unpriced
+// flavor once per process. Flavors still missing after a sync, such as
+// deleted or private flavors, only trigger the next sync when it is due.
+func (m *Monitor) checkFlavors() {
+	if m.nhnClient == nil {
+		m.warnUnpriced(nil)
+		return
+	}
+
+	region := m.nhnClient.Region()
+	stale := time.Since(m.flavorStorage.LastSync(region)) > flavorSyncInterval
+	for key := range m.missingFlavorKeys() {
+		if !m.missingFlavors[key] {
+			stale = true
+			break
+		}
+	}
+
+	if !stale {
+		m.warnUnpriced(nil)
+		return
+	}
+
+	result, err := m.SyncFlavors()
+	if err != nil {
+		log.Printf("경고: flavor 동기화 실패: %v", err)
+		return
+	}
+	m.missingFlavors = m.missingFlavorKeys()
+	log.Printf("flavor %d개를 동기화했습니다. (새 flavor: %d개)", result.Total, len(result.Added))
+	m.warnUnpriced(result.Unpriced)
+}
+
+// missingFlavorKeys returns the flavors of live NHN Cloud instances that are
+// not in the catalog, keyed by region and flavor ID
+func (m *Monitor) missingFlavorKeys() map[string]bool {
+	missing := make(map[string]bool)
+	for _, instance := range m.instanceStorage.GetAllInstances() {
+		if instance.DeletedAt != nil || instance.FlavorID == "" || instanceCSP(instance) != nhncloud.CSPName {
+			continue
+		}
+		if _, exists := m.flavorStorage.GetFlavor(instance.Region, instance.FlavorID); !exists {
+			missing[instance.Region+"/"+instance.FlavorID] = true
+		}
+	}
+	return missing
+}
+
+// warnUnpriced logs each unpriced flavor once per process. A nil list is
+// computed from the stored instances.
+func (m *Monitor) warnUnpriced(unpriced []UnpricedFlavor) {
+	if unpriced == nil {
+		var err error
+		if unpriced, err = m.UnpricedFlavors(); err != nil {
+			log.Printf("경고: 가격 미등록 flavor 확인 실패: %v", err)
+			return
+		}
+	}
+
+	for _, flavor := range unpriced {
+		if m.warnedFlavors[flavor.FlavorID] {
+			continue
+		}
+		m.warnedFlavors[flavor.FlavorID] = true
+		log.Printf("경고: 가격 정보가 없는 flavor %s를 인스턴스 %d개가 사용 중입니다.", DescribeFlavor(flavor), len(flavor.Instances))
+	}
+}
+
+// DescribeFlavor formats an unpriced flavor as "name (id, specs)"
+func DescribeFlavor(flavor UnpricedFlavor) string {
+	if flavor.Spec == nil {
+		return flavor.FlavorID
+	}
+	return fmt.Sprintf("%s (%s, vCPU %d, 메모리 %dMB, 디스크 %dGB)",
+		flavor.Spec.Name, flavor.FlavorID, flavor.Spec.VCPU, flavor.Spec.MemoryMB, flavor.Spec.DiskGB)
+}
+
+func (m *Monitor) flavorFile() string {
+	if m.config.Storage.FlavorFile != "" {
+		return m.config.Storage.FlavorFile
+	}
+	return storage.FlavorFilePath(m.config.Storage.InstanceFile)
+}
@@ -5,9 +5,11 @@ import (
 	"log"
 	"time"
 
-	"cost-collect/pkg/config"
+	"cost-collect/pkg/aws"
+	"cost-collect/pkg/navercloud"
 	"cost-collect/pkg/nhncloud"
 	"cost-collect/pkg/storage"
+	"costconfig/config"
 )
 
 // Stats holds monitoring statistics.
@@ -16,13 +18,35 @@ type Stats struct {
 	TotalInstances    int
 	RunningInstances  int
 	ShutdownInstances int
+	DeletedInstances  int
+	// 상태별 인스턴스 수 (ACTIVE, SHUTOFF, SHELVED_OFFLOADED 등)
+	StatusCounts map[string]int
+}
+
+// Provider lists the instances of one CSP
+type Provider interface {
+	Name() string
+	CSP() string
+	GetInstances() ([]*storage.InstanceState, error)
+	GetDeletedInstances(since time.Time) (map[string]time.Time, error)
+}
+
+// skippingProvider is implemented by providers that leave out instances
+// they cannot classify. Those instances keep their stored state and are not
+// recorded as deleted.
+type skippingProvider interface {
+	SkippedInstances() map[string]string
 }
 
 // Monitor manages the collection of instance data.
 type Monitor struct {
 	config          *config.Config
 	instanceStorage *storage.InstanceStateStorage
-	nhnClient       *nhncloud.Client
+	flavorStorage   *storage.FlavorStorage
+	providers       []Provider
+	nhnClient       *nhncloud.Client // NHN Cloud가 설정되지 않았으면 nil
+	warnedFlavors   map[string]bool
+	missingFlavors  map[string]bool // 마지막 동기화 후에도 카탈로그에 없는 flavor (리전/ID)
 	stats           Stats
 	ticker          *time.Ticker
 	done            chan bool
@@ -30,19 +54,36 @@ type Monitor struct {
 
 // NewMonitor creates a new Monitor.
 func NewMonitor(cfg *config.Config) *Monitor {
-	storage := storage.NewInstanceStateStorage()
-	if err := storage.LoadFromFile(cfg.Storage.InstanceFile); err != nil {
+	instanceStorage := storage.NewInstanceStateStorage()
+	if err := instanceStorage.LoadFromFile(cfg.Storage.InstanceFile); err != nil {
 		log.Printf("경고: 기존 인스턴스 데이터 로딩 실패 (%s): %v", cfg.Storage.InstanceFile, err)
 	}
 
-	nhnClient := nhncloud.NewClient(&cfg.NHNCloud)
-
-	return &Monitor{
+	m := &Monitor{
 		config:          cfg,
-		instanceStorage: storage,
-		nhnClient:       nhnClient,
+		instanceStorage: instanceStorage,
+		warnedFlavors:   make(map[string]bool),
+		missingFlavors:  make(map[string]bool),
 		done:            make(chan bool),
 	}
+
+	if cfg.NHNCloud.Configured() {
+		m.nhnClient = nhncloud.NewClient(&cfg.NHNCloud)
+		m.providers = append(m.providers, m.nhnClient)
+	}
+	if cfg.AWS.Configured() {
+		m.providers = append(m.providers, aws.NewClient(&cfg.AWS))
+	}
+	if cfg.NaverCloud.Configured() {
+		m.providers = append(m.providers, navercloud.NewClient(&cfg.NaverCloud))
+	}
+
+	m.flavorStorage = storage.NewFlavorStorage()
+	if err := m.flavorStorage.LoadFromFile(m.flavorFile()); err != nil {
+		log.Printf("경고: 기존 flavor 데이터 로딩 실패 (%s): %v", m.flavorFile(), err)
+	}
+
+	return m
 }
 
 // Start begins the monitoring loop.
@@ -91,30 +132,99 @@ func (m *Monitor) GetStats() Stats {
 func (m *Monitor) update() {
 	log.Println("데이터를 수집하고 업데이트합니다...")
 
-	// 1. Fetch data from NHN Cloud API
-	instances, err := m.nhnClient.GetInstances()
-	if err != nil {
-		log.Printf("오류: NHN Cloud API에서 데이터를 가져오지 못했습니다: %v", err)
-		return
-	}
+	// 1. Fetch data from each CSP and update instance storage.
+	// 조회에 실패한 CSP의 인스턴스는 삭제로 처리하지 않음
+	collected := 0
+	for _, provider := range m.providers {
+		instances, err := provider.GetInstances()
+		if err != nil {
+			log.Printf("오류: %s API에서 데이터를 가져오지 못했습니다: %v", provider.Name(), err)
+			continue
+		}
+		collected++
 
-	log.Printf("NHN Cloud API에서 %d개의 인스턴스를 가져왔습니다.", len(instances))
+		log.Printf("%s API에서 %d개의 인스턴스를 가져왔습니다.", provider.Name(), len(instances))
 
-	// 2. Update instance storage
-	for _, instance := range instances {
-		m.instanceStorage.UpdateInstance(instance)
+		// 2. Update instance storage
+		seen := make(map[string]bool, len(instances))
+		for _, instance := range instances {
+			m.instanceStorage.UpdateInstance(instance)
+			seen[instance.ID] = true
+		}
+		if skipper, ok := provider.(skippingProvider); ok {
+			for id, reason := range skipper.SkippedInstances() {
+				log.Printf("경고: %s 인스턴스 %s를 건너뜁니다: %s", provider.Name(), id, reason)
+				seen[id] = true
+			}
+		}
+		m.markDeleted(provider, seen)
+	}
+	if collected == 0 {
+		return
 	}
+	m.instanceStorage.LastUpdate = time.Now()
 
 	// 3. Save to file
 	if err := m.instanceStorage.SaveToFile(m.config.Storage.InstanceFile); err != nil {
 		log.Printf("오류: 인스턴스 데이터를 파일에 저장하지 못했습니다: %v", err)
 	}
 
-	// 4. Recalculate summary stats
+	// 4. Sync flavors and report the ones without a price
+	m.checkFlavors()
+
+	// 5. Recalculate summary stats
 	m.recalculateStats()
 
-	log.Printf("업데이트 완료. 총 %d개 인스턴스 (실행 중: %d개, 정지: %d개)", 
-		m.stats.TotalInstances, m.stats.RunningInstances, m.stats.ShutdownInstances)
+	log.Printf("업데이트 완료. 총 %d개 인스턴스 (실행 중: %d개, 정지: %d개, 삭제: %d개)", 
+		m.stats.TotalInstances, m.stats.RunningInstances, m.stats.ShutdownInstances, m.stats.DeletedInstances)
+}
+
+// markDeleted records a DELETED transition for the provider's stored
+// instances that are no longer listed. The deletion time reported by the CSP
+// is used when available, otherwise the time the disappearance was detected.
+func (m *Monitor) markDeleted(provider Provider, seen map[string]bool) {
+	var missing []string
+	since := time.Now()
+	for id, instance := range m.instanceStorage.GetAllInstances() {
+		if seen[id] || instance.DeletedAt != nil || instanceCSP(instance) != provider.CSP() {
+			continue
+		}
+		missing = append(missing, id)
+		if instance.LastUpdated.Before(since) {
+			since = instance.LastUpdated
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	deletedTimes, err := provider.GetDeletedInstances(since)
+	if err != nil {
+		log.Printf("경고: 삭제된 인스턴스 조회 실패, 감지 시각으로 기록합니다: %v", err)
+	}
+
+	source := storage.DeletedAtSourceAPI
+	if provider.CSP() == nhncloud.CSPName {
+		source = storage.DeletedAtSourceNova
+	}
+	now := time.Now()
+	for _, id := range missing {
+		if deletedAt, ok := deletedTimes[id]; ok {
+			m.instanceStorage.MarkDeleted(id, deletedAt, source)
+		} else {
+			m.instanceStorage.MarkDeleted(id, now, storage.DeletedAtSourceDetected)
+		}
+		log.Printf("인스턴스 %s 삭제를 감지했습니다.", id)
+	}
+}
+
+// instanceCSP returns the CSP an instance was collected from. Instances saved
+// before CSPs were recorded come from NHN Cloud.
+func instanceCSP(instance *storage.InstanceState) string {
+	if instance.CSP == "" {
+		return nhncloud.CSPName
+	}
+	return instance.CSP
 }
 
 func (m *Monitor) recalculateStats() {
@@ -122,10 +232,16 @@ func (m *Monitor) recalculateStats() {
 	m.stats.TotalInstances = len(instances)
 	m.stats.RunningInstances = 0
 	m.stats.ShutdownInstances = 0
+	m.stats.DeletedInstances = 0
+	m.stats.StatusCounts = make(map[string]int)
 	for _, instance := range instances {
-		if instance.CurrentStatus == "ACTIVE" {
+		m.stats.StatusCounts[storage.EffectiveStatus(instance.CurrentStatus, instance.CurrentPowerState)]++
+		switch {
+		case instance.DeletedAt != nil:
+			m.stats.DeletedInstances++
+		case storage.IsRunningStatus(instance.CurrentStatus, instance.CurrentPowerState):
 			m.stats.RunningInstances++
-		} else {
+		default:
 			m.stats.ShutdownInstances++
 		}
 	}
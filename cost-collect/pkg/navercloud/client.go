@@ -0,0 +1,293 @@
+package navercloud
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"cost-collect/pkg/storage"
+	"costconfig/config"
+)
+
+// CSPName is the pricing file CSP that NCP servers are priced under
+const CSPName = "naver"
+
+// DefaultRegion is collected when no region is configured
+const DefaultRegion = "KR"
+
+// 한 번에 조회할 서버 수
+const pageSize = 100
+
+// NCP server instance status codes (serverInstanceStatus.code)
+const (
+	StatusInit       = "INIT"
+	StatusCreating   = "CREAT"
+	StatusRunning    = "RUN"
+	StatusStopped    = "NSTOP"
+	StatusTerminated = "TERMT"
+)
+
+// NCP API 시각 형식 (예: 2025-08-20T13:59:07+0900)
+const timeLayout = "2006-01-02T15:04:05-0700"
+
+type Client struct {
+	config     *config.NaverCloudConfig
+	httpClient *http.Client
+
+	// 마지막 조회에서 상태 코드를 알 수 없어 제외한 서버 (서버 번호 -> 사유)
+	skipped map[string]string
+}
+
+type ServerInstanceListResponse struct {
+	Response ServerInstanceList `json:"getServerInstanceListResponse"`
+}
+
+type ServerInstanceList struct {
+	ReturnCode     string           `json:"returnCode"`
+	ReturnMessage  string           `json:"returnMessage"`
+	TotalRows      int              `json:"totalRows"`
+	ServerInstance []ServerInstance `json:"serverInstanceList"`
+}
+
+type ServerInstance struct {
+	ServerInstanceNo  string     `json:"serverInstanceNo"`
+	ServerName        string     `json:"serverName"`
+	Status            CommonCode `json:"serverInstanceStatus"`
+	CreateDate        string     `json:"createDate"`
+	Uptime            string     `json:"uptime"`
+	ServerProductCode string     `json:"serverProductCode"`
+	ServerSpecCode    string     `json:"serverSpecCode"`
+	RegionCode        string     `json:"regionCode"`
+	ZoneCode          string     `json:"zoneCode"`
+}
+
+type CommonCode struct {
+	Code     string `json:"code"`
+	CodeName string `json:"codeName"`
+}
+
+type ErrorResponse struct {
+	Error struct {
+		ReturnCode    string `json:"returnCode"`
+		ReturnMessage string `json:"returnMessage"`
+	} `json:"responseError"`
+}
+
+func NewClient(cfg *config.NaverCloudConfig) *Client {
+	return &Client{
+		config: cfg,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Name is the provider name shown in logs
+func (c *Client) Name() string {
+	return "Naver Cloud"
+}
+
+// CSP returns the pricing file CSP of the collected instances
+func (c *Client) CSP() string {
+	return CSPName
+}
+
+// Regions returns the configured region codes, or KR when none is set
+func (c *Client) Regions() []string {
+	if len(c.config.Regions) == 0 {
+		return []string{DefaultRegion}
+	}
+	return c.config.Regions
+}
+
+// GetInstances lists the VPC servers of every configured region. Servers
+// being terminated are left out so that they are recorded as deleted, and
+// servers with an unknown status are left out and reported by
+// SkippedInstances.
+func (c *Client) GetInstances() ([]*storage.InstanceState, error) {
+	var instances []*storage.InstanceState
+	now := time.Now()
+	c.skipped = make(map[string]string)
+
+	for _, region := range c.Regions() {
+		servers, err := c.listServers(region)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", region, err)
+		}
+
+		for _, server := range servers {
+			status, powerState, err := mapStatus(server.Status.Code)
+			if err != nil {
+				c.skipped[server.ServerInstanceNo] = fmt.Sprintf("%s (%s): %v", server.ServerName, region, err)
+				continue
+			}
+			if status == storage.StatusDeleted {
+				continue
+			}
+
+			createdAt, err := time.Parse(timeLayout, server.CreateDate)
+			if err != nil {
+				createdAt = now
+			}
+
+			// uptime은 마지막으로 시작된 시각이므로 실행 중일 때만 상태 변경 시각으로 사용.
+			// 서버 목록에는 정지된 시각이 없으므로 정지된 서버는 생성 시각을 그대로 보내,
+			// 처음 조회한 서버를 생성 후 계속 실행 중이었던 것으로 기록하지 않도록 함
+			// (실행 중에서 정지로 바뀌면 저장소가 감지한 시각으로 기록)
+			updatedAt := createdAt
+			if uptime, err := time.Parse(timeLayout, server.Uptime); err == nil && status == storage.StatusActive && uptime.After(updatedAt) {
+				updatedAt = uptime
+			}
+
+			// 리전 코드(KR)가 아닌 존 코드(KR-1)를 가격 파일의 리전으로 사용
+			zone := server.ZoneCode
+			if zone == "" {
+				zone = server.RegionCode
+			}
+
+			var metadata map[string]string
+			if server.ServerSpecCode != "" {
+				metadata = map[string]string{"server_spec_code": server.ServerSpecCode}
+			}
+
+			instances = append(instances, &storage.InstanceState{
+				ID:                server.ServerInstanceNo,
+				Name:              server.ServerName,
+				FlavorID:          server.ServerProductCode,
+				Region:            zone,
+				CSP:               CSPName,
+				CurrentStatus:     status,
+				CurrentPowerState: powerState,
+				CreatedAt:         createdAt,
+				LastUpdated:       updatedAt,
+				LastSeen:          now,
+				Metadata:          metadata,
+			})
+		}
+	}
+
+	return instances, nil
+}
+
+// GetDeletedInstances always returns an empty result. NCP has no API that
+// lists returned servers with their return time: getServerInstanceList
+// drops a server once it has been returned and shows it as TERMT only while
+// it is being returned, without a timestamp. Deleted servers are therefore
+// recorded at the time their disappearance is detected, at most one
+// collection interval after the return.
+func (c *Client) GetDeletedInstances(since time.Time) (map[string]time.Time, error) {
+	return map[string]time.Time{}, nil
+}
+
+// SkippedInstances returns the servers left out of the last GetInstances
+// because of an unknown status code, with the reason. They are neither
+// updated nor recorded as deleted.
+func (c *Client) SkippedInstances() map[string]string {
+	return c.skipped
+}
+
+// listServers calls getServerInstanceList page by page until totalRows
+// servers have been read.
+func (c *Client) listServers(region string) ([]ServerInstance, error) {
+	var servers []ServerInstance
+	for pageNo := 1; ; pageNo++ {
+		query := url.Values{}
+		query.Set("regionCode", region)
+		query.Set("pageNo", strconv.Itoa(pageNo))
+		query.Set("pageSize", strconv.Itoa(pageSize))
+		query.Set("responseFormatType", "json")
+
+		var page ServerInstanceListResponse
+		if err := c.getJSON("/vserver/v2/getServerInstanceList?"+query.Encode(), &page); err != nil {
+			return nil, err
+		}
+		if page.Response.ReturnCode != "" && page.Response.ReturnCode != "0" {
+			return nil, fmt.Errorf("서버 목록 조회 실패 (%s): %s", page.Response.ReturnCode, page.Response.ReturnMessage)
+		}
+
+		servers = append(servers, page.Response.ServerInstance...)
+		if len(page.Response.ServerInstance) == 0 || len(servers) >= page.Response.TotalRows {
+			return servers, nil
+		}
+	}
+}
+
+// getJSON sends a GET request signed with the NCP API gateway signature
+// (x-ncp-apigw-signature-v2) and decodes the JSON response.
+func (c *Client) getJSON(uri string, out interface{}) error {
+	req, err := http.NewRequest("GET", c.endpoint()+uri, nil)
+	if err != nil {
+		return fmt.Errorf("요청 생성 실패: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	req.Header.Set("x-ncp-apigw-timestamp", timestamp)
+	req.Header.Set("x-ncp-iam-access-key", c.config.AccessKey)
+	req.Header.Set("x-ncp-apigw-signature-v2", signature(c.config.SecretKey, req.Method, req.URL.RequestURI(), timestamp, c.config.AccessKey))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("서버 목록 요청 실패: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("응답 읽기 실패: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp ErrorResponse
+		if json.Unmarshal(data, &errResp) == nil && errResp.Error.ReturnCode != "" {
+			return fmt.Errorf("서버 목록 조회 실패 (상태코드: %d): %s: %s", resp.StatusCode, errResp.Error.ReturnCode, errResp.Error.ReturnMessage)
+		}
+		return fmt.Errorf("서버 목록 조회 실패 (상태코드: %d): %s", resp.StatusCode, string(data))
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("응답 파싱 실패: %w", err)
+	}
+
+	return nil
+}
+
+// endpoint returns the configured API gateway or the public NCP gateway
+func (c *Client) endpoint() string {
+	if c.config.Endpoint != "" {
+		return strings.TrimRight(c.config.Endpoint, "/")
+	}
+	return "https://ncloud.apigw.ntruss.com"
+}
+
+// signature computes base64(HMAC-SHA256(secretKey, "METHOD URI\ntimestamp\naccessKey"))
+func signature(secretKey, method, uri, timestamp, accessKey string) string {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(method + " " + uri + "\n" + timestamp + "\n" + accessKey))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// mapStatus converts an NCP server status into the Nova status and power
+// state used by the instance storage. Servers being returned map to
+// DELETED. Unknown codes are an error so that they are never billed under
+// a guessed status.
+func mapStatus(code string) (status string, powerState int, err error) {
+	switch code {
+	case StatusInit, StatusCreating:
+		return storage.StatusBuild, storage.PowerStateNoState, nil
+	case StatusRunning:
+		return storage.StatusActive, storage.PowerStateRunning, nil
+	case StatusStopped:
+		return storage.StatusShutoff, storage.PowerStateShutdown, nil
+	case StatusTerminated:
+		return storage.StatusDeleted, storage.PowerStateNoState, nil
+	}
+	return "", storage.PowerStateNoState, fmt.Errorf("알 수 없는 서버 상태 코드 %q", code)
+}
@@ -0,0 +1,76 @@
+package navercloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cost-collect/pkg/storage"
+	"costconfig/config"
+)
+
+// newServerListStub serves getServerInstanceList with the given servers in
+// a single page
+func newServerListStub(t *testing.T, servers []ServerInstance) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vserver/v2/getServerInstanceList" || r.Header.Get("x-ncp-apigw-signature-v2") == "" {
+			t.Errorf("unexpected request %s %v", r.URL, r.Header)
+		}
+		json.NewEncoder(w).Encode(ServerInstanceListResponse{Response: ServerInstanceList{
+			ReturnCode:     "0",
+			TotalRows:      len(servers),
+			ServerInstance: servers,
+		}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetInstancesStatuses(t *testing.T) {
+	server := newServerListStub(t, []ServerInstance{
+		{ServerInstanceNo: "1", ServerName: "run", Status: CommonCode{Code: StatusRunning}, CreateDate: "2025-08-01T09:00:00+0900", Uptime: "2025-08-05T09:00:00+0900", ZoneCode: "KR-1"},
+		{ServerInstanceNo: "2", ServerName: "stop", Status: CommonCode{Code: StatusStopped}, CreateDate: "2025-08-01T09:00:00+0900", Uptime: "2025-08-05T09:00:00+0900", ZoneCode: "KR-1"},
+		{ServerInstanceNo: "3", ServerName: "returning", Status: CommonCode{Code: StatusTerminated}, CreateDate: "2025-08-01T09:00:00+0900", ZoneCode: "KR-1"},
+		{ServerInstanceNo: "4", ServerName: "odd", Status: CommonCode{Code: "REPAIR"}, CreateDate: "2025-08-01T09:00:00+0900", ZoneCode: "KR-1"},
+	})
+	client := NewClient(&config.NaverCloudConfig{Endpoint: server.URL, AccessKey: "ak", SecretKey: "sk"})
+
+	instances, err := client.GetInstances()
+	if err != nil {
+		t.Fatalf("GetInstances: %v", err)
+	}
+
+	got := make(map[string]*storage.InstanceState)
+	for _, instance := range instances {
+		got[instance.ID] = instance
+	}
+	if len(got) != 2 || got["1"] == nil || got["2"] == nil {
+		t.Fatalf("instances = %v, want servers 1 and 2", got)
+	}
+
+	if got["1"].CurrentStatus != storage.StatusActive || !got["1"].LastUpdated.Equal(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("running server = %s at %s, want ACTIVE since uptime", got["1"].CurrentStatus, got["1"].LastUpdated)
+	}
+	// 정지 시각이 없으므로 생성 시각을 보내 생성 후 실행 중이었던 것으로 추론되지 않게 함
+	if got["2"].CurrentStatus != storage.StatusShutoff || !got["2"].LastUpdated.Equal(got["2"].CreatedAt) {
+		t.Errorf("stopped server = %s at %s, want SHUTOFF since creation", got["2"].CurrentStatus, got["2"].LastUpdated)
+	}
+
+	skipped := client.SkippedInstances()
+	if _, ok := skipped["4"]; !ok || len(skipped) != 1 {
+		t.Errorf("skipped = %v, want only server 4", skipped)
+	}
+}
+
+func TestMapStatusUnknownCode(t *testing.T) {
+	for _, code := range []string{StatusInit, StatusCreating, StatusRunning, StatusStopped, StatusTerminated} {
+		if _, _, err := mapStatus(code); err != nil {
+			t.Errorf("mapStatus(%s): %v", code, err)
+		}
+	}
+	if status, _, err := mapStatus("FSTOP"); err == nil {
+		t.Errorf("mapStatus(FSTOP) = %s, want an error", status)
+	}
+}
@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
-	"cost-collect/pkg/config"
 	"cost-collect/pkg/storage"
+	"costconfig/config"
 )
 
+// CSPName is the pricing file CSP that NHN Cloud instances are priced under
+const CSPName = "nhn"
+
 type Client struct {
 	config    *config.NHNCloudConfig
 	token     string
@@ -73,10 +77,35 @@ type NovaServer struct {
 	Updated        string                 `json:"updated"`
 	Flavor         map[string]interface{} `json:"flavor"`
 	OSExtSTSPower  int                    `json:"OS-EXT-STS:power_state"`
+	Metadata       map[string]string      `json:"metadata"`
+	Tags           []string               `json:"tags"`
+	TerminatedAt   string                 `json:"OS-SRV-USG:terminated_at"`
 }
 
 type NovaServersResponse struct {
-	Servers []NovaServer `json:"servers"`
+	Servers      []NovaServer `json:"servers"`
+	ServersLinks []NovaLink   `json:"servers_links"`
+}
+
+type NovaFlavor struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	VCPUs     int    `json:"vcpus"`
+	RAM       int    `json:"ram"`
+	Disk      int    `json:"disk"`
+	Ephemeral int    `json:"OS-FLV-EXT-DATA:ephemeral"`
+	IsPublic  bool   `json:"os-flavor-access:is_public"`
+	Disabled  bool   `json:"OS-FLV-DISABLED:disabled"`
+}
+
+type NovaFlavorsResponse struct {
+	Flavors      []NovaFlavor `json:"flavors"`
+	FlavorsLinks []NovaLink   `json:"flavors_links"`
+}
+
+type NovaLink struct {
+	Rel  string `json:"rel"`
+	Href string `json:"href"`
 }
 
 func NewClient(cfg *config.NHNCloudConfig) *Client {
@@ -161,45 +190,15 @@ func (c *Client) ensureAuthenticated() error {
 }
 
 func (c *Client) GetInstances() ([]*storage.InstanceState, error) {
-	if err := c.ensureAuthenticated(); err != nil {
-		return nil, fmt.Errorf("인증 실패: %w", err)
-	}
-
-	// NHN Cloud의 Nova API 엔드포인트
-	computeURL := c.config.ComputeURL
-	if computeURL == "" {
-		computeURL = "https://kr1-api-instance-infrastructure.nhncloudservice.com"
-	}
-	url := fmt.Sprintf("%s/v2/%s/servers/detail", computeURL, c.config.TenantID)
-
-	req, err := http.NewRequest("GET", url, nil)
+	servers, err := c.listServers(nil)
 	if err != nil {
-		return nil, fmt.Errorf("요청 생성 실패: %w", err)
-	}
-
-	req.Header.Set("X-Auth-Token", c.token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("인스턴스 목록 요청 실패: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("인스턴스 목록 조회 실패 (상태코드: %d): %s", resp.StatusCode, string(body))
-	}
-
-	var novaResp NovaServersResponse
-	if err := json.NewDecoder(resp.Body).Decode(&novaResp); err != nil {
-		return nil, fmt.Errorf("응답 파싱 실패: %w", err)
+		return nil, err
 	}
 
-	instances := make([]*storage.InstanceState, 0, len(novaResp.Servers))
+	instances := make([]*storage.InstanceState, 0, len(servers))
 	now := time.Now()
 
-	for _, server := range novaResp.Servers {
+	for _, server := range servers {
 		createdAt, err := time.Parse(time.RFC3339, server.Created)
 		if err != nil {
 			createdAt = now
@@ -217,24 +216,195 @@ func (c *Client) GetInstances() ([]*storage.InstanceState, error) {
 			flavorID = flavor
 		}
 
-		// Power State 추출
-		powerState := server.OSExtSTSPower
-		if powerState == 0 {
-			powerState = 1 // 기본값
-		}
-
 		instance := &storage.InstanceState{
 			ID:                server.ID,
 			Name:              server.Name,
 			FlavorID:          flavorID,
+			Region:            c.Region(),
+			CSP:               CSPName,
 			CurrentStatus:     server.Status,
-			CurrentPowerState: powerState,
+			CurrentPowerState: server.OSExtSTSPower, // BUILD, SHELVED_OFFLOADED 등은 0 (NOSTATE)
 			CreatedAt:         createdAt,
 			LastUpdated:       updatedAt, // API의 updated 시간 사용
+			LastSeen:          now,       // 목록에서 마지막으로 확인한 시각
+			Metadata:          server.Metadata,
+			Tags:              server.Tags,
 		}
 
 		instances = append(instances, instance)
 	}
 
 	return instances, nil
-}
\ No newline at end of file
+}
+
+// GetDeletedInstances returns the deletion time of servers deleted since the
+// given time, using Nova's changes-since filter which also lists deleted
+// servers. The termination time is preferred over the last update time.
+func (c *Client) GetDeletedInstances(since time.Time) (map[string]time.Time, error) {
+	query := url.Values{}
+	query.Set("changes-since", since.UTC().Format(time.RFC3339))
+
+	servers, err := c.listServers(query)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make(map[string]time.Time)
+	for _, server := range servers {
+		if server.Status != storage.StatusDeleted {
+			continue
+		}
+
+		for _, value := range []string{server.TerminatedAt, server.Updated} {
+			if value == "" {
+				continue
+			}
+			// terminated_at은 시간대 없이 내려오는 경우가 있어 UTC로 해석
+			if deletedAt, err := time.Parse(time.RFC3339, value); err == nil {
+				deleted[server.ID] = deletedAt
+				break
+			}
+			if deletedAt, err := time.Parse("2006-01-02T15:04:05.000000", value); err == nil {
+				deleted[server.ID] = deletedAt
+				break
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// listServers calls /servers/detail with the given query and follows the
+// "next" links, so instances beyond the first page are not mistaken for
+// deleted ones.
+func (c *Client) listServers(query url.Values) ([]NovaServer, error) {
+	if err := c.ensureAuthenticated(); err != nil {
+		return nil, fmt.Errorf("인증 실패: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/v2/%s/servers/detail", c.computeURL(), c.config.TenantID)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var servers []NovaServer
+	for endpoint != "" {
+		page, err := c.getServersPage(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, page.Servers...)
+
+		endpoint = ""
+		for _, link := range page.ServersLinks {
+			if link.Rel == "next" {
+				endpoint = link.Href
+			}
+		}
+	}
+
+	return servers, nil
+}
+
+func (c *Client) getServersPage(endpoint string) (*NovaServersResponse, error) {
+	var novaResp NovaServersResponse
+	if err := c.getJSON(endpoint, "인스턴스 목록", &novaResp); err != nil {
+		return nil, err
+	}
+	return &novaResp, nil
+}
+
+// GetFlavors returns the specs of every flavor visible to the tenant in the
+// configured region, following the "next" links of /flavors/detail.
+func (c *Client) GetFlavors() ([]*storage.FlavorSpec, error) {
+	if err := c.ensureAuthenticated(); err != nil {
+		return nil, fmt.Errorf("인증 실패: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/v2/%s/flavors/detail", c.computeURL(), c.config.TenantID)
+
+	var flavors []*storage.FlavorSpec
+	for endpoint != "" {
+		var page NovaFlavorsResponse
+		if err := c.getJSON(endpoint, "flavor 목록", &page); err != nil {
+			return nil, err
+		}
+		for _, flavor := range page.Flavors {
+			flavors = append(flavors, &storage.FlavorSpec{
+				ID:          flavor.ID,
+				Name:        flavor.Name,
+				VCPU:        flavor.VCPUs,
+				MemoryMB:    flavor.RAM,
+				DiskGB:      flavor.Disk,
+				EphemeralGB: flavor.Ephemeral,
+				Public:      flavor.IsPublic,
+				Disabled:    flavor.Disabled,
+			})
+		}
+
+		endpoint = ""
+		for _, link := range page.FlavorsLinks {
+			if link.Rel == "next" {
+				endpoint = link.Href
+			}
+		}
+	}
+
+	return flavors, nil
+}
+
+// Name is the provider name shown in logs
+func (c *Client) Name() string {
+	return "NHN Cloud"
+}
+
+// CSP returns the pricing file CSP of the collected instances
+func (c *Client) CSP() string {
+	return CSPName
+}
+
+// Region returns the configured region, or KR1 when the default KR1 endpoint
+// is used without one
+func (c *Client) Region() string {
+	if c.config.Region == "" && c.config.ComputeURL == "" {
+		return "KR1"
+	}
+	return c.config.Region
+}
+
+// computeURL returns the Nova endpoint, defaulting to the KR1 region
+func (c *Client) computeURL() string {
+	if c.config.ComputeURL != "" {
+		return c.config.ComputeURL
+	}
+	return "https://kr1-api-instance-infrastructure.nhncloudservice.com"
+}
+
+// getJSON sends an authenticated GET request and decodes the JSON response.
+// what names the requested resource in error messages.
+func (c *Client) getJSON(endpoint, what string, out interface{}) error {
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("요청 생성 실패: %w", err)
+	}
+
+	req.Header.Set("X-Auth-Token", c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s 요청 실패: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s 조회 실패 (상태코드: %d): %s", what, resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("응답 파싱 실패: %w", err)
+	}
+
+	return nil
+}
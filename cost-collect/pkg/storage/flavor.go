@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FlavorSpec is a flavor as reported by Nova's /flavors/detail
+type FlavorSpec struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	VCPU        int    `json:"vcpu"`
+	MemoryMB    int    `json:"memory_mb"`
+	DiskGB      int    `json:"disk_gb"`
+	EphemeralGB int    `json:"ephemeral_gb,omitempty"`
+	Public      bool   `json:"public"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+// RegionFlavors holds the flavors of one region as of the last sync
+type RegionFlavors struct {
+	LastSync time.Time              `json:"last_sync"`
+	Flavors  map[string]*FlavorSpec `json:"flavors"`
+}
+
+// FlavorStorage is the flavor catalog synced from Nova, keyed by region
+type FlavorStorage struct {
+	Regions map[string]*RegionFlavors `json:"regions"`
+}
+
+// DefaultFlavorRegion is the region key used when no region is configured
+const DefaultFlavorRegion = "default"
+
+func NewFlavorStorage() *FlavorStorage {
+	return &FlavorStorage{
+		Regions: make(map[string]*RegionFlavors),
+	}
+}
+
+// FlavorFilePath returns the flavor catalog stored next to the instance file
+func FlavorFilePath(instanceFile string) string {
+	return filepath.Join(filepath.Dir(instanceFile), "flavors.json")
+}
+
+func (s *FlavorStorage) LoadFromFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // 동기화 전이면 빈 목록으로 시작
+		}
+		return fmt.Errorf("파일 읽기 실패: %w", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return fmt.Errorf("JSON 파싱 실패: %w", err)
+	}
+	if s.Regions == nil {
+		s.Regions = make(map[string]*RegionFlavors)
+	}
+
+	return nil
+}
+
+func (s *FlavorStorage) SaveToFile(filename string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSON 마샬링 실패: %w", err)
+	}
+
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("파일 쓰기 실패: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateRegion replaces the flavors of a region and returns the IDs of
+// flavors that were not known before, sorted.
+func (s *FlavorStorage) UpdateRegion(region string, flavors []*FlavorSpec, syncedAt time.Time) []string {
+	region = flavorRegionKey(region)
+	previous := s.Regions[region]
+
+	updated := &RegionFlavors{
+		LastSync: syncedAt,
+		Flavors:  make(map[string]*FlavorSpec, len(flavors)),
+	}
+	var added []string
+	for _, flavor := range flavors {
+		updated.Flavors[flavor.ID] = flavor
+		if previous == nil || previous.Flavors[flavor.ID] == nil {
+			added = append(added, flavor.ID)
+		}
+	}
+	s.Regions[region] = updated
+
+	sort.Strings(added)
+	return added
+}
+
+// GetFlavor looks a flavor up in the given region
+func (s *FlavorStorage) GetFlavor(region, flavorID string) (*FlavorSpec, bool) {
+	flavors, exists := s.Regions[flavorRegionKey(region)]
+	if !exists {
+		return nil, false
+	}
+	flavor, exists := flavors.Flavors[flavorID]
+	return flavor, exists
+}
+
+// LastSync returns when the region's flavors were last synced
+func (s *FlavorStorage) LastSync(region string) time.Time {
+	if flavors, exists := s.Regions[flavorRegionKey(region)]; exists {
+		return flavors.LastSync
+	}
+	return time.Time{}
+}
+
+func flavorRegionKey(region string) string {
+	if region == "" {
+		return DefaultFlavorRegion
+	}
+	return region
+}
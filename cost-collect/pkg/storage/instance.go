@@ -11,13 +11,34 @@ type InstanceState struct {
 	ID                string              `json:"id"`
 	Name              string              `json:"name"`
 	FlavorID          string              `json:"flavor_id"`
+	Region            string              `json:"region,omitempty"`
+	CSP               string              `json:"csp,omitempty"` // 비어 있으면 가격 파일의 default_csp
 	CurrentStatus     string              `json:"current_status"`
 	CurrentPowerState int                 `json:"current_power_state"`
 	CreatedAt         time.Time           `json:"created_at"`
 	LastUpdated       time.Time           `json:"last_updated"`
+	LastSeen          time.Time           `json:"last_seen,omitzero"`
 	StatusHistory     []StatusHistoryItem `json:"status_history"`
+	Metadata          map[string]string   `json:"metadata,omitempty"`
+	Tags              []string            `json:"tags,omitempty"`
+	DeletedAt         *time.Time          `json:"deleted_at,omitempty"`
+	DeletedAtSource   string              `json:"deleted_at_source,omitempty"`
+	FlavorHistory     []FlavorChange      `json:"flavor_history,omitempty"`
 }
 
+// FlavorChange records that the instance used FlavorID from Since onwards
+type FlavorChange struct {
+	FlavorID string    `json:"flavor_id"`
+	Since    time.Time `json:"since"`
+}
+
+// Deletion time sources
+const (
+	DeletedAtSourceNova     = "nova"     // Nova changes-since 응답의 삭제 시각
+	DeletedAtSourceAPI      = "api"      // Nova 외 CSP API가 보고한 삭제 시각
+	DeletedAtSourceDetected = "detected" // 목록에서 사라진 것을 감지한 시각
+)
+
 type StatusHistoryItem struct {
 	Status     string    `json:"status"`
 	PowerState int       `json:"power_state"`
@@ -71,14 +92,12 @@ func (s *InstanceStateStorage) UpdateInstance(newInstance *InstanceState) {
 	if !ok {
 		// 새로운 인스턴스 - updated 시간 기반 히스토리 생성
 		s.createInitialHistory(newInstance)
-		s.limitHistorySize(newInstance)
 		s.Instances[newInstance.ID] = newInstance
 		return
 	}
 
 	// 기존 인스턴스 업데이트
 	s.updateExistingInstance(existingInstance, newInstance)
-	s.limitHistorySize(existingInstance)
 }
 
 // createInitialHistory는 새로운 인스턴스의 초기 히스토리를 생성합니다.
@@ -90,25 +109,15 @@ func (s *InstanceStateStorage) createInitialHistory(instance *InstanceState) {
 	if !instance.LastUpdated.Equal(instance.CreatedAt) && 
 	   instance.LastUpdated.After(instance.CreatedAt.Add(1*time.Minute)) {
 		
-		// 이전 상태 추론: 현재 상태의 반대 상태로 가정
-		var previousStatus string
-		var previousPowerState int
-		
-		if instance.CurrentStatus == "ACTIVE" {
-			previousStatus = "SHUTOFF"
-			previousPowerState = 4
-		} else {
-			previousStatus = "ACTIVE" 
-			previousPowerState = 1
+		// 이전 상태 추론: 실행 중이면 정지, 그 외 상태는 실행 중이었다고 가정
+		if status, powerState, ok := previousStatus(instance.CurrentStatus, instance.CurrentPowerState); ok {
+			previousHistoryItem := StatusHistoryItem{
+				Status:     status,
+				PowerState: powerState,
+				Timestamp:  instance.CreatedAt,
+			}
+			history = append(history, previousHistoryItem)
 		}
-		
-		// 이전 상태를 created 시간에 추가
-		previousHistoryItem := StatusHistoryItem{
-			Status:     previousStatus,
-			PowerState: previousPowerState,
-			Timestamp:  instance.CreatedAt,
-		}
-		history = append(history, previousHistoryItem)
 	}
 	
 	// 현재 상태 추가
@@ -126,30 +135,86 @@ func (s *InstanceStateStorage) createInitialHistory(instance *InstanceState) {
 func (s *InstanceStateStorage) updateExistingInstance(existingInstance, newInstance *InstanceState) {
 	// LastUpdated 시간 비교로 실제 상태 변경 감지
 	isRealUpdate := newInstance.LastUpdated.After(existingInstance.LastUpdated)
+
+	// updated 시간이 그대로여도 상태나 전원 상태가 바뀌었으면 기록
+	statusChanged := EffectiveStatus(newInstance.CurrentStatus, newInstance.CurrentPowerState) !=
+		EffectiveStatus(existingInstance.CurrentStatus, existingInstance.CurrentPowerState)
 	
+	// flavor 변경(리사이즈) 기록. 상태 이력과 마찬가지로 비용 계산에 전체 구간이 필요하므로 개수를 제한하지 않음
+	if newInstance.FlavorID != "" && newInstance.FlavorID != existingInstance.FlavorID {
+		changedAt := newInstance.LastUpdated
+		if !isRealUpdate {
+			changedAt = time.Now()
+		}
+		if len(existingInstance.FlavorHistory) == 0 {
+			existingInstance.FlavorHistory = append(existingInstance.FlavorHistory, FlavorChange{
+				FlavorID: existingInstance.FlavorID,
+				Since:    existingInstance.CreatedAt,
+			})
+		}
+		existingInstance.FlavorHistory = append(existingInstance.FlavorHistory, FlavorChange{
+			FlavorID: newInstance.FlavorID,
+			Since:    changedAt,
+		})
+		existingInstance.FlavorID = newInstance.FlavorID
+	}
+
 	// 기존 인스턴스 정보 업데이트
 	existingInstance.CurrentStatus = newInstance.CurrentStatus
 	existingInstance.CurrentPowerState = newInstance.CurrentPowerState
-	existingInstance.LastUpdated = newInstance.LastUpdated
-
-	// updated 시간이 실제로 변경된 경우에만 히스토리 추가
-	// (API의 updated 시간이 변경되었다는 것은 실제 상태 변경이 있었음을 의미)
 	if isRealUpdate {
+		existingInstance.LastUpdated = newInstance.LastUpdated
+	}
+	existingInstance.Name = newInstance.Name
+	existingInstance.Region = newInstance.Region
+	existingInstance.CSP = newInstance.CSP
+	existingInstance.Metadata = newInstance.Metadata
+	existingInstance.Tags = newInstance.Tags
+	existingInstance.LastSeen = newInstance.LastSeen
+	existingInstance.DeletedAt = nil
+	existingInstance.DeletedAtSource = ""
+
+	// updated 시간이 변경되었거나 상태가 바뀐 경우에만 히스토리 추가
+	// (API의 updated 시간이 변경되었다는 것은 실제 상태 변경이 있었음을 의미,
+	// updated 없이 상태만 바뀌면 관측 시각을 변경 시점으로 사용)
+	if isRealUpdate || statusChanged {
+		timestamp := existingInstance.LastUpdated
+		if !isRealUpdate {
+			timestamp = time.Now()
+			existingInstance.LastUpdated = timestamp
+		}
 		newHistoryItem := StatusHistoryItem{
 			Status:     newInstance.CurrentStatus,
 			PowerState: newInstance.CurrentPowerState,
-			Timestamp:  newInstance.LastUpdated,
+			Timestamp:  timestamp,
 		}
 		existingInstance.StatusHistory = append(existingInstance.StatusHistory, newHistoryItem)
 	}
 }
 
-// limitHistorySize는 인스턴스의 히스토리를 최신 3개로 제한합니다.
-func (s *InstanceStateStorage) limitHistorySize(instance *InstanceState) {
-	const maxHistorySize = 3
-	if len(instance.StatusHistory) > maxHistorySize {
-		instance.StatusHistory = instance.StatusHistory[len(instance.StatusHistory)-maxHistorySize:]
+// MarkDeleted는 목록에서 사라진 인스턴스에 DELETED 상태 변경을 기록합니다.
+// 삭제된 인스턴스는 수명 기간의 비용 조회를 위해 상태 이력 전체와 함께 저장소에 남겨둡니다.
+func (s *InstanceStateStorage) MarkDeleted(id string, deletedAt time.Time, source string) {
+	instance, ok := s.Instances[id]
+	if !ok || instance.DeletedAt != nil {
+		return
+	}
+
+	// 마지막 기록보다 이른 시각으로 기록되지 않도록 보정
+	if deletedAt.Before(instance.LastUpdated) {
+		deletedAt = instance.LastUpdated
 	}
+
+	instance.CurrentStatus = StatusDeleted
+	instance.CurrentPowerState = PowerStateNoState
+	instance.LastUpdated = deletedAt
+	instance.DeletedAt = &deletedAt
+	instance.DeletedAtSource = source
+	instance.StatusHistory = append(instance.StatusHistory, StatusHistoryItem{
+		Status:     StatusDeleted,
+		PowerState: PowerStateNoState,
+		Timestamp:  deletedAt,
+	})
 }
 
 func (s *InstanceStateStorage) GetAllInstances() map[string]*InstanceState {
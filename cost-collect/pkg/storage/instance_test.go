@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatusHistoryKeptUntilDeletion(t *testing.T) {
+	s := NewInstanceStateStorage()
+	created := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	changes := []struct {
+		status     string
+		powerState int
+	}{
+		{StatusActive, PowerStateRunning},
+		{StatusShutoff, PowerStateShutdown},
+		{StatusActive, PowerStateRunning},
+		{StatusShutoff, PowerStateShutdown},
+		{StatusActive, PowerStateRunning},
+	}
+	for i, change := range changes {
+		s.UpdateInstance(&InstanceState{
+			ID:                "vm-1",
+			FlavorID:          "m2.c2m4",
+			CurrentStatus:     change.status,
+			CurrentPowerState: change.powerState,
+			CreatedAt:         created,
+			LastUpdated:       created.Add(time.Duration(i) * 24 * time.Hour),
+		})
+	}
+	deletedAt := created.Add(10 * 24 * time.Hour)
+	s.MarkDeleted("vm-1", deletedAt, DeletedAtSourceNova)
+
+	history := s.Instances["vm-1"].StatusHistory
+	if len(history) != len(changes)+1 {
+		t.Fatalf("status history has %d entries, want %d: %+v", len(history), len(changes)+1, history)
+	}
+	if !history[0].Timestamp.Equal(created) || history[0].Status != StatusActive {
+		t.Errorf("first entry = %+v, want ACTIVE at creation", history[0])
+	}
+	for i, change := range changes {
+		if history[i].Status != change.status {
+			t.Errorf("entry %d status = %s, want %s", i, history[i].Status, change.status)
+		}
+	}
+	if last := history[len(history)-1]; last.Status != StatusDeleted || !last.Timestamp.Equal(deletedAt) {
+		t.Errorf("last entry = %+v, want DELETED at %s", last, deletedAt)
+	}
+}
+
+func TestStoppedServerWithoutStopTime(t *testing.T) {
+	s := NewInstanceStateStorage()
+	created := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	stopped := func() *InstanceState {
+		// 정지 시각을 주지 않는 CSP는 정지된 서버의 updated로 생성 시각을 보냄
+		return &InstanceState{ID: "vm-1", CurrentStatus: StatusShutoff, CurrentPowerState: PowerStateShutdown, CreatedAt: created, LastUpdated: created}
+	}
+
+	// 몇 달 동안 정지되어 있던 서버를 처음 조회하면 생성 시각부터 정지로 기록
+	s.UpdateInstance(stopped())
+	s.UpdateInstance(stopped())
+	history := s.Instances["vm-1"].StatusHistory
+	if len(history) != 1 || history[0].Status != StatusShutoff || !history[0].Timestamp.Equal(created) {
+		t.Fatalf("status history = %+v, want only SHUTOFF since creation", history)
+	}
+
+	// 다시 시작하면 uptime을, 다시 정지하면 감지한 시각을 변경 시점으로 기록
+	started := created.Add(90 * 24 * time.Hour)
+	s.UpdateInstance(&InstanceState{ID: "vm-1", CurrentStatus: StatusActive, CurrentPowerState: PowerStateRunning, CreatedAt: created, LastUpdated: started})
+	before := time.Now()
+	s.UpdateInstance(stopped())
+
+	history = s.Instances["vm-1"].StatusHistory
+	if len(history) != 3 {
+		t.Fatalf("status history has %d entries, want SHUTOFF, ACTIVE, SHUTOFF: %+v", len(history), history)
+	}
+	if history[1].Status != StatusActive || !history[1].Timestamp.Equal(started) {
+		t.Errorf("start entry = %+v, want ACTIVE at %s", history[1], started)
+	}
+	if history[2].Status != StatusShutoff || history[2].Timestamp.Before(before) {
+		t.Errorf("stop entry = %+v, want SHUTOFF at the detection time", history[2])
+	}
+}
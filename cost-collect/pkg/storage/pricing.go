@@ -147,6 +147,11 @@ func (p *PricingStorage) LoadFromFile(filename string) error {
 
 // GetFlavorPrice supports both legacy and new formats
 func (p *PricingStorage) GetFlavorPrice(flavorID string) (*FlavorPrice, bool) {
+	return p.GetCSPFlavorPrice("", flavorID)
+}
+
+// GetCSPFlavorPrice is GetFlavorPrice for a CSP (the default CSP when empty)
+func (p *PricingStorage) GetCSPFlavorPrice(cspName, flavorID string) (*FlavorPrice, bool) {
 	// Check legacy format first
 	if len(p.Flavors) > 0 {
 		price, exists := p.Flavors[flavorID]
@@ -155,12 +160,14 @@ func (p *PricingStorage) GetFlavorPrice(flavorID string) (*FlavorPrice, bool) {
 	
 	// Check new format
 	if p.NewPricingSchema != nil {
-		defaultCSP := p.DefaultCSP
-		if defaultCSP == "" {
-			defaultCSP = "nhn" // fallback
+		if cspName == "" {
+			cspName = p.DefaultCSP
+		}
+		if cspName == "" {
+			cspName = "nhn" // fallback
 		}
 		
-		if csp, exists := p.CSPs[defaultCSP]; exists {
+		if csp, exists := p.CSPs[cspName]; exists {
 			if instanceType, exists := csp.InstanceTypes[flavorID]; exists {
 				currency := csp.DefaultCurrency
 				if pricing, exists := instanceType.Pricing[currency]; exists {
@@ -0,0 +1,80 @@
+package storage
+
+import "strings"
+
+// OpenStack server statuses (Nova server.status)
+const (
+	StatusActive           = "ACTIVE"
+	StatusBuild            = "BUILD"
+	StatusShutoff          = "SHUTOFF"
+	StatusPaused           = "PAUSED"
+	StatusSuspended        = "SUSPENDED"
+	StatusShelved          = "SHELVED"
+	StatusShelvedOffloaded = "SHELVED_OFFLOADED"
+	StatusRescue           = "RESCUE"
+	StatusError            = "ERROR"
+	StatusResize           = "RESIZE"
+	StatusVerifyResize     = "VERIFY_RESIZE"
+	StatusDeleted          = "DELETED"
+	StatusUnknown          = "UNKNOWN"
+)
+
+// Nova power states (OS-EXT-STS:power_state)
+const (
+	PowerStateNoState   = 0
+	PowerStateRunning   = 1
+	PowerStatePaused    = 3
+	PowerStateShutdown  = 4
+	PowerStateCrashed   = 6
+	PowerStateSuspended = 7
+)
+
+// EffectiveStatus combines a Nova status with its power state. An ACTIVE
+// server whose guest is shut down, paused or suspended is reported as that
+// state; a missing power state on an ACTIVE server is treated as running.
+func EffectiveStatus(status string, powerState int) string {
+	status = strings.ToUpper(strings.TrimSpace(status))
+
+	switch status {
+	case StatusActive:
+		switch powerState {
+		case PowerStateShutdown, PowerStateCrashed:
+			return StatusShutoff
+		case PowerStatePaused:
+			return StatusPaused
+		case PowerStateSuspended:
+			return StatusSuspended
+		}
+		return StatusActive
+
+	case "":
+		switch powerState {
+		case PowerStateRunning:
+			return StatusActive
+		case PowerStateShutdown:
+			return StatusShutoff
+		}
+		return StatusUnknown
+	}
+
+	return status
+}
+
+// IsRunningStatus reports whether the guest is up and running
+func IsRunningStatus(status string, powerState int) bool {
+	return EffectiveStatus(status, powerState) == StatusActive
+}
+
+// previousStatus guesses the state an instance was in before its first
+// observation, when Nova reports it was updated after creation. Instances
+// still building or failed have not been in any other state.
+func previousStatus(status string, powerState int) (string, int, bool) {
+	switch EffectiveStatus(status, powerState) {
+	case StatusBuild, StatusError:
+		return "", 0, false
+	case StatusActive:
+		return StatusShutoff, PowerStateShutdown, true
+	default:
+		return StatusActive, PowerStateRunning, true
+	}
+}
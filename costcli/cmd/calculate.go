@@ -8,21 +8,26 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"costcli/pkg/config"
 	"costcli/pkg/calculator"
 	"costcli/pkg/storage"
+	"costconfig/config"
 )
 
-var configPath string
 var outputFormat string
 var period string
+var currency string
+var strict bool
 
 var calculateCmd = &cobra.Command{
 	Use:   "calculate",
 	Short: "비용 계산",
-	Long:  `현재 인스턴스 사용량과 할인 정책을 기반으로 비용을 계산합니다.`,
+	Long: `현재 인스턴스 사용량과 할인 정책을 기반으로 비용을 계산합니다.
+
+가격 파일에 없는 flavor는 CSP의 fallback_pricing(vCPU/메모리 단가)이 있으면 사양으로 가격을 추정하고,
+없으면 해당 인스턴스를 합계에서 제외하고 경고와 함께 가격 미등록 인스턴스로 표시합니다.
+--strict를 지정하면 가격이 없는 flavor가 하나라도 있을 때 계산을 중단합니다.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.LoadConfig(configPath)
+		cfg, err := config.LoadConfig(env.ConfigPath)
 		if err != nil {
 			return fmt.Errorf("설정 로딩 실패: %w", err)
 		}
@@ -37,7 +42,15 @@ var calculateCmd = &cobra.Command{
 			return fmt.Errorf("가격 정보 로딩 실패: %w", err)
 		}
 
+		flavorStorage := storage.NewFlavorStorage()
+		if err := flavorStorage.LoadFromFile(flavorFilePath(cfg)); err != nil {
+			return fmt.Errorf("flavor 정보 로딩 실패: %w", err)
+		}
+
 		calc := calculator.NewCostCalculator(pricingStorage)
+		calc.SetCurrency(currency)
+		calc.SetFlavorStorage(flavorStorage)
+		calc.SetStrict(strict)
 
 		var summary *calculator.CostSummary
 		switch period {
@@ -76,38 +89,120 @@ func outputTable(summary *calculator.CostSummary) error {
 	fmt.Printf("=== 비용 계산 결과 ===\n")
 	fmt.Printf("기간: %s ~ %s\n", summary.Period.StartTime.In(kst).Format("2006-01-02 15:04"), summary.Period.EndTime.In(kst).Format("2006-01-02 15:04"))
 	fmt.Printf("총 인스턴스: %d개\n", summary.TotalInstances)
-	fmt.Printf("기본 비용: %.2f %s\n", summary.TotalBaseCost, summary.Currency)
-	fmt.Printf("총 할인: %.2f %s\n", summary.TotalDiscount, summary.Currency)
-	fmt.Printf("최종 비용: %.2f %s\n\n", summary.TotalFinalCost, summary.Currency)
+	fmt.Printf("기본 비용: %s %s\n", summary.TotalBaseCost, summary.Currency)
+	fmt.Printf("총 할인: %s %s\n", summary.TotalDiscount, summary.Currency)
+	fmt.Printf("최종 비용: %s %s\n", summary.TotalFinalCost, summary.Currency)
+	for _, rate := range summary.ExchangeRates {
+		fmt.Printf("적용 환율: 1 %s = %.6g %s (기준: %s)\n", rate.From, rate.Rate, rate.To, rate.LastUpdated)
+	}
+	for _, warning := range summary.Warnings {
+		fmt.Printf("⚠️  경고: %s\n", warning)
+	}
+	fmt.Println()
 
 	for _, instance := range summary.InstanceCosts {
 		fmt.Printf("인스턴스: %s (%s)\n", instance.InstanceName, instance.InstanceID)
-		fmt.Printf("  - Flavor: %s (%.2f %s/시간)\n", instance.FlavorName, instance.BaseHourlyRate, summary.Currency)
+		if instance.DeletedAt != nil {
+			fmt.Printf("  - 삭제: %s\n", instance.DeletedAt.In(kst).Format("2006-01-02 15:04"))
+		}
+		if len(summary.CSPSubtotals) > 1 {
+			fmt.Printf("  - CSP: %s\n", instance.CSP)
+		}
+		fmt.Printf("  - Flavor: %s (%s %s/시간)\n", instance.FlavorName, instance.BaseHourlyRate, summary.Currency)
+		for _, flavorID := range instance.UnavailableFlavors {
+			fmt.Printf("  - ⚠️  flavor %s는 리전 %s에서 제공되지 않는 것으로 등록되어 있습니다 (availability 확인)\n", flavorID, instance.Region)
+		}
+		if instance.EstimatedPrice {
+			fmt.Printf("  - 추정 가격 (vCPU/메모리 단가)\n")
+		}
+		if instance.PriceSource == "converted" {
+			fmt.Printf("  - 환산 가격 (환율 %.6g)\n", instance.ExchangeRate)
+		}
 		fmt.Printf("  - 실행 시간: %.2f시간\n", instance.TotalRunningHours)
-		fmt.Printf("  - 기본 비용: %.2f %s\n", instance.BaseCost, summary.Currency)
-		fmt.Printf("  - 할인: %.2f %s\n", instance.TotalDiscount, summary.Currency)
-		fmt.Printf("  - 최종 비용: %.2f %s\n", instance.FinalCost, summary.Currency)
+		if instance.BillingModel != "" && instance.BillingModel != storage.BillingModelHourly {
+			fmt.Printf("  - 과금 방식: %s\n", billingModelLabel(instance.BillingModel))
+		}
+		if instance.TotalStoppedHours > 0 {
+			fmt.Printf("  - 정지 시간: %.2f시간\n", instance.TotalStoppedHours)
+		}
+		if instance.TotalStoppedHours > 0 || isSplit(instance) {
+			fmt.Printf("  - 과금 항목:\n")
+			for _, item := range instance.LineItems {
+				label := lineItemLabel(item.Type)
+				if item.FlavorID != "" {
+					label = fmt.Sprintf("%s [%s, %s ~ %s]", label, item.FlavorName, item.Start.In(kst).Format("01-02 15:04"), item.End.In(kst).Format("01-02 15:04"))
+				}
+				fmt.Printf("    * %s: %.2f시간 × %s = %s %s\n", label, item.Hours, item.HourlyRate, item.Amount, summary.Currency)
+			}
+		}
+		fmt.Printf("  - 기본 비용: %s %s\n", instance.BaseCost, summary.Currency)
+		fmt.Printf("  - 할인: %s %s\n", instance.TotalDiscount, summary.Currency)
+		fmt.Printf("  - 최종 비용: %s %s\n", instance.FinalCost, summary.Currency)
 		
 		if len(instance.AppliedDiscounts) > 0 {
 			fmt.Printf("  - 적용된 할인:\n")
 			for _, discount := range instance.AppliedDiscounts {
-				fmt.Printf("    * %s (%.1f%%): %.2f %s\n", discount.RuleName, discount.DiscountPercent, discount.DiscountAmount, summary.Currency)
+				fmt.Printf("    * %s (%s): %s %s\n", discount.RuleName, discountLabel(discount), discount.DiscountAmount, summary.Currency)
+				if discount.Resolution != "" {
+					fmt.Printf("      %s\n", discount.Resolution)
+				}
+				if discount.EffectiveRatio > 0 && discount.EffectiveRatio < 1 {
+					fmt.Printf("      적용 기간: %s ~ %s (%.1f%% 안분)\n", discount.EffectiveFrom.In(kst).Format("2006-01-02 15:04"), discount.EffectiveTo.In(kst).Format("2006-01-02 15:04"), discount.EffectiveRatio*100)
+				}
+			}
+		}
+		if len(instance.SkippedDiscounts) > 0 {
+			fmt.Printf("  - 제외된 할인:\n")
+			for _, discount := range instance.SkippedDiscounts {
+				fmt.Printf("    * %s (%s): %s\n", discount.RuleName, discountLabel(discount), discount.Resolution)
 			}
 		}
 		fmt.Println()
 	}
 
+	if len(summary.Unpriced) > 0 {
+		fmt.Printf("=== 가격 미등록 인스턴스 (%d개, 합계에서 제외) ===\n", len(summary.Unpriced))
+		for _, instance := range summary.Unpriced {
+			fmt.Printf("  - %s (%s): flavor %s", instance.InstanceName, instance.InstanceID, instance.FlavorName)
+			if instance.FlavorName != instance.FlavorID {
+				fmt.Printf(" (%s)", instance.FlavorID)
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+	}
+
 	// 총계 요약 다시 표시
 	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
 	fmt.Printf("=== 💰 총 비용 요약 ===\n")
 	fmt.Printf("📅 계산 기간: %s ~ %s\n", summary.Period.StartTime.In(kst).Format("2006-01-02 15:04"), summary.Period.EndTime.In(kst).Format("2006-01-02 15:04"))
 	fmt.Printf("🖥️  총 인스턴스: %d개\n", summary.TotalInstances)
-	fmt.Printf("💵 기본 비용: %.2f %s\n", summary.TotalBaseCost, summary.Currency)
-	fmt.Printf("🎟️  총 할인: %.2f %s\n", summary.TotalDiscount, summary.Currency)
-	fmt.Printf("🏷️  최종 비용: %.2f %s\n", summary.TotalFinalCost, summary.Currency)
+	if len(summary.Unpriced) > 0 {
+		fmt.Printf("⚠️  가격 미등록으로 제외: %d개\n", len(summary.Unpriced))
+	}
+	fmt.Printf("💵 기본 비용: %s %s\n", summary.TotalBaseCost, summary.Currency)
+	fmt.Printf("🎟️  총 할인: %s %s\n", summary.TotalDiscount, summary.Currency)
+	for _, discount := range summary.SummaryDiscounts {
+		name := discount.RuleName
+		if discount.CSP != "" {
+			name = fmt.Sprintf("[%s] %s", discount.CSP, name)
+		}
+		fmt.Printf("   - %s (%s): %s %s\n", name, discountLabel(discount), discount.DiscountAmount, summary.Currency)
+	}
+	if len(summary.CSPSubtotals) > 1 {
+		fmt.Printf("☁️  CSP별 소계:\n")
+		for _, subtotal := range summary.CSPSubtotals {
+			fmt.Printf("   - %s (%d개): %s %s", subtotal.CSP, subtotal.Instances, subtotal.FinalCost, summary.Currency)
+			if subtotal.ExchangeRate > 0 {
+				fmt.Printf(" (%s %s)", subtotal.NativeFinalCost, subtotal.NativeFinalCost.Currency())
+			}
+			fmt.Println()
+		}
+	}
+	fmt.Printf("🏷️  최종 비용: %s %s\n", summary.TotalFinalCost, summary.Currency)
 	
-	if summary.TotalDiscount > 0 {
-		discountRate := (summary.TotalDiscount / summary.TotalBaseCost) * 100
+	if summary.TotalDiscount.IsPositive() {
+		discountRate := summary.TotalDiscount.Ratio(summary.TotalBaseCost) * 100
 		fmt.Printf("📊 할인율: %.1f%%\n", discountRate)
 	}
 	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
@@ -115,10 +210,71 @@ func outputTable(summary *calculator.CostSummary) error {
 	return nil
 }
 
+// flavorFilePath returns the flavor catalog synced by cost-collect
+func flavorFilePath(cfg *config.Config) string {
+	if cfg.Storage.FlavorFile != "" {
+		return cfg.Storage.FlavorFile
+	}
+	return storage.FlavorFilePath(cfg.Storage.InstanceFile)
+}
+
+// discountLabel describes how a discount was calculated
+func discountLabel(discount calculator.DiscountDetail) string {
+	switch discount.Method {
+	case storage.DiscountMethodFixed:
+		return "고정 금액"
+	case storage.DiscountMethodTiered:
+		return "구간별 할인"
+	default:
+		return fmt.Sprintf("%.1f%%", discount.DiscountPercent)
+	}
+}
+
+// lineItemLabel describes a base cost component
+func lineItemLabel(itemType string) string {
+	switch itemType {
+	case calculator.LineItemRunning:
+		return "실행"
+	case calculator.LineItemStopped:
+		return "정지 (감면 요금)"
+	case calculator.LineItemStoppedAfterWindow:
+		return "정지 (감면 기간 경과)"
+	case calculator.LineItemStorage:
+		return "보관 (스토리지 요금)"
+	default:
+		return itemType
+	}
+}
+
+// isSplit reports whether the line items were split by flavor or price change
+func isSplit(instance calculator.InstanceCost) bool {
+	for _, item := range instance.LineItems {
+		if item.FlavorID != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// billingModelLabel describes how the base cost was charged
+func billingModelLabel(model string) string {
+	switch model {
+	case storage.BillingModelMonthlyCap:
+		return "시간제 (월 요금 상한)"
+	case storage.ContractMonthly:
+		return "월 약정 (기간 비례)"
+	case storage.ContractYearly:
+		return "연 약정 (기간 비례)"
+	default:
+		return model
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(calculateCmd)
 
-	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "설정 파일 경로")
 	calculateCmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "출력 형식 (table, json)")
 	calculateCmd.Flags().StringVarP(&period, "period", "p", "current", "계산 기간 (daily, monthly, current)")
+	calculateCmd.Flags().StringVar(&currency, "currency", "", "출력 통화 (예: KRW, USD, 기본값: CSP 기본 통화)")
+	calculateCmd.Flags().BoolVar(&strict, "strict", false, "가격이 없는 flavor가 있으면 계산 중단")
 }
\ No newline at end of file
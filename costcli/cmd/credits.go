@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"costcli/pkg/calculator"
+	"costcli/pkg/money"
+	"costcli/pkg/storage"
+	"costconfig/config"
+)
+
+var (
+	creditID        string
+	creditName      string
+	creditAmount    float64
+	creditCurrency  string
+	creditFrom      string
+	creditTo        string
+	creditRegions   []string
+	creditFlavors   []string
+	creditInstances []string
+)
+
+var creditsCmd = &cobra.Command{
+	Use:   "credits",
+	Short: "크레딧 잔액 조회",
+	Long:  `선불 크레딧과 약정 금액의 사용량, 잔액, 예상 소진일을 조회합니다.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig(env.ConfigPath)
+		if err != nil {
+			return fmt.Errorf("설정 로딩 실패: %w", err)
+		}
+
+		creditStorage := storage.NewCreditStorage()
+		if err := creditStorage.LoadFromFile(creditFilePath(cfg)); err != nil {
+			return fmt.Errorf("크레딧 정보 로딩 실패: %w", err)
+		}
+
+		stateStorage := storage.NewInstanceStateStorage()
+		if err := stateStorage.LoadFromFile(cfg.Storage.InstanceFile); err != nil {
+			return fmt.Errorf("인스턴스 상태 로딩 실패: %w", err)
+		}
+
+		pricingStorage := storage.NewPricingStorage()
+		if err := pricingStorage.LoadFromFile(cfg.Storage.PriceFile); err != nil {
+			return fmt.Errorf("가격 정보 로딩 실패: %w", err)
+		}
+
+		calc := calculator.NewCostCalculator(pricingStorage)
+		balances, err := calc.CalculateCreditBalances(stateStorage.GetAllInstances(), creditStorage.Credits, time.Now())
+		if err != nil {
+			return fmt.Errorf("크레딧 사용량 계산 실패: %w", err)
+		}
+
+		switch outputFormat {
+		case "json":
+			return outputJSON(balances)
+		default:
+			return outputCreditBalances(balances)
+		}
+	},
+}
+
+var creditsAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "크레딧 추가",
+	Long:  `크레딧 원장에 선불 크레딧 또는 약정 금액을 추가합니다.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig(env.ConfigPath)
+		if err != nil {
+			return fmt.Errorf("설정 로딩 실패: %w", err)
+		}
+
+		if creditID == "" || creditAmount <= 0 {
+			return fmt.Errorf("--id와 0보다 큰 --amount 값이 필요합니다")
+		}
+
+		validFrom := time.Now()
+		if creditFrom != "" {
+			validFrom, err = time.Parse(time.RFC3339, creditFrom)
+			if err != nil {
+				return fmt.Errorf("--from 형식 오류 (RFC3339): %w", err)
+			}
+		}
+
+		credit := storage.Credit{
+			ID:        creditID,
+			Name:      creditName,
+			Amount:    creditAmount,
+			Currency:  creditCurrency,
+			ValidFrom: validFrom,
+			Scope: storage.CreditScope{
+				Regions:     creditRegions,
+				FlavorIDs:   creditFlavors,
+				InstanceIDs: creditInstances,
+			},
+		}
+		if credit.Name == "" {
+			credit.Name = credit.ID
+		}
+		if creditTo != "" {
+			validTo, err := time.Parse(time.RFC3339, creditTo)
+			if err != nil {
+				return fmt.Errorf("--to 형식 오류 (RFC3339): %w", err)
+			}
+			credit.ValidTo = &validTo
+		}
+
+		creditFile := creditFilePath(cfg)
+		creditStorage := storage.NewCreditStorage()
+		if err := creditStorage.LoadFromFile(creditFile); err != nil {
+			return fmt.Errorf("크레딧 정보 로딩 실패: %w", err)
+		}
+
+		if err := creditStorage.AddCredit(credit); err != nil {
+			return err
+		}
+
+		if err := creditStorage.SaveToFile(creditFile); err != nil {
+			return fmt.Errorf("크레딧 정보 저장 실패: %w", err)
+		}
+
+		fmt.Printf("크레딧이 추가되었습니다: %s (%.2f %s)\n", credit.ID, credit.Amount, credit.Currency)
+		return nil
+	},
+}
+
+// creditFilePath returns the configured credit ledger or the default one
+// next to the pricing file.
+func creditFilePath(cfg *config.Config) string {
+	if cfg.Storage.CreditFile != "" {
+		return cfg.Storage.CreditFile
+	}
+	return storage.CreditFilePath(cfg.Storage.PriceFile)
+}
+
+func outputCreditBalances(balances []calculator.CreditBalance) error {
+	kst, _ := time.LoadLocation("Asia/Seoul")
+
+	fmt.Printf("=== 크레딧 잔액 ===\n")
+	fmt.Printf("총 크레딧: %d개\n\n", len(balances))
+
+	for _, balance := range balances {
+		credit := balance.Credit
+		fmt.Printf("크레딧: %s (%s)\n", credit.Name, credit.ID)
+		validTo := "제한 없음"
+		if credit.ValidTo != nil {
+			validTo = credit.ValidTo.In(kst).Format("2006-01-02")
+		}
+		fmt.Printf("  - 유효 기간: %s ~ %s\n", credit.ValidFrom.In(kst).Format("2006-01-02"), validTo)
+		fmt.Printf("  - 금액: %.2f %s\n", credit.Amount, credit.Currency)
+		fmt.Printf("  - 사용: %s %s\n", balance.Used, credit.Currency)
+		fmt.Printf("  - 잔액: %s %s\n", balance.Remaining, credit.Currency)
+		fmt.Printf("  - 일평균 사용 (최근 7일): %s %s\n", balance.DailyBurn.Round(money.DefaultDecimals(credit.Currency), money.RoundHalfUp), credit.Currency)
+
+		switch {
+		case balance.Expired:
+			fmt.Printf("  - 상태: 만료됨\n")
+		case !balance.Remaining.IsPositive():
+			fmt.Printf("  - 상태: 소진됨\n")
+		case balance.ProjectedExhaustion != nil:
+			fmt.Printf("  - 예상 소진일: %s\n", balance.ProjectedExhaustion.In(kst).Format("2006-01-02"))
+		default:
+			fmt.Printf("  - 예상 소진일: 유효 기간 내 소진되지 않음\n")
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func init() {
+	creditsAddCmd.Flags().StringVar(&creditID, "id", "", "크레딧 ID")
+	creditsAddCmd.Flags().StringVar(&creditName, "name", "", "크레딧 이름")
+	creditsAddCmd.Flags().Float64Var(&creditAmount, "amount", 0, "크레딧 금액")
+	creditsAddCmd.Flags().StringVar(&creditCurrency, "currency", "KRW", "크레딧 통화")
+	creditsAddCmd.Flags().StringVar(&creditFrom, "from", "", "유효 시작 시각 (RFC3339, 기본값: 현재)")
+	creditsAddCmd.Flags().StringVar(&creditTo, "to", "", "유효 종료 시각 (RFC3339, 기본값: 제한 없음)")
+	creditsAddCmd.Flags().StringSliceVar(&creditRegions, "region", nil, "적용 리전 (여러 번 지정 가능)")
+	creditsAddCmd.Flags().StringSliceVar(&creditFlavors, "flavor", nil, "적용 flavor ID (여러 번 지정 가능)")
+	creditsAddCmd.Flags().StringSliceVar(&creditInstances, "instance", nil, "적용 인스턴스 ID (여러 번 지정 가능)")
+
+	creditsCmd.AddCommand(creditsAddCmd)
+	rootCmd.AddCommand(creditsCmd)
+
+	creditsCmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "출력 형식 (table, json)")
+}
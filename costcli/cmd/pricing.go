@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"costcli/pkg/storage"
+)
+
+var (
+	pricingDiffFrom       string
+	pricingDiffTo         string
+	pricingValidateStrict bool
+)
+
+var pricingCmd = &cobra.Command{
+	Use:   "pricing",
+	Short: "가격 정보 관리",
+	Long:  `가격 파일(pricing.json)의 내용을 조회, 비교, 검증합니다.`,
+}
+
+var pricingValidateCmd = &cobra.Command{
+	Use:   "validate [FILE]",
+	Short: "가격 파일 검증",
+	Long: `가격 파일의 구조와 내용을 검사합니다. 파일을 지정하지 않으면 설정된 가격 파일을 검사합니다.
+
+JSON 구조와 값 형식, 알 수 없는 필드, 조건 타입과 연산자, 중복된 규칙 ID, 기본 통화 가격 누락,
+시간당/월/연 요금 불일치, 날짜 형식, 알 수 없는 리전을 확인합니다. 오류가 있으면 종료 코드 1을 반환합니다.`,
+	Args:          cobra.MaximumNArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := pricingFilePath()
+		if len(args) == 1 {
+			file, err = args[0], nil
+		}
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("가격 파일 읽기 실패: %w", err)
+		}
+
+		issues := storage.ValidatePricing(data)
+
+		switch outputFormat {
+		case "json":
+			if err := outputJSON(issues); err != nil {
+				return err
+			}
+		default:
+			outputValidationIssues(file, issues)
+		}
+
+		errorCount := 0
+		for _, issue := range issues {
+			if issue.Severity == storage.SeverityError || pricingValidateStrict {
+				errorCount++
+			}
+		}
+		if errorCount > 0 {
+			return fmt.Errorf("가격 파일 검증 실패: %d건", errorCount)
+		}
+		return nil
+	},
+}
+
+var pricingDiffCmd = &cobra.Command{
+	Use:   "diff [OLD_FILE NEW_FILE]",
+	Short: "가격 버전 비교",
+	Long: `두 가격 버전 사이에 추가, 삭제, 변경된 flavor 가격을 보여줍니다.
+
+파일을 두 개 지정하면 두 가격 파일을 비교하고, 지정하지 않으면 설정된 가격 파일의
+--from 시점과 --to 시점(기본값: 현재) 가격을 price_history 기준으로 비교합니다.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 && len(args) != 2 {
+			return fmt.Errorf("비교할 가격 파일을 두 개 지정하거나 지정하지 않아야 합니다")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := parseOptionalTime(pricingDiffFrom)
+		if err != nil {
+			return fmt.Errorf("--from %w", err)
+		}
+		to, err := parseOptionalTime(pricingDiffTo)
+		if err != nil {
+			return fmt.Errorf("--to %w", err)
+		}
+
+		var oldFile, newFile string
+		if len(args) == 2 {
+			oldFile, newFile = args[0], args[1]
+		} else {
+			if from.IsZero() {
+				return fmt.Errorf("파일을 지정하지 않으면 --from 값이 필요합니다")
+			}
+			file, err := pricingFilePath()
+			if err != nil {
+				return err
+			}
+			oldFile, newFile = file, file
+		}
+
+		oldPricing := storage.NewPricingStorage()
+		if err := oldPricing.LoadFromFile(oldFile); err != nil {
+			return fmt.Errorf("가격 정보 로딩 실패 (%s): %w", oldFile, err)
+		}
+		newPricing := storage.NewPricingStorage()
+		if err := newPricing.LoadFromFile(newFile); err != nil {
+			return fmt.Errorf("가격 정보 로딩 실패 (%s): %w", newFile, err)
+		}
+
+		diffs := storage.DiffCatalogs(oldPricing.CatalogAt(from), newPricing.CatalogAt(to))
+
+		switch outputFormat {
+		case "json":
+			return outputJSON(diffs)
+		default:
+			return outputPriceDiffs(diffs)
+		}
+	},
+}
+
+// outputValidationIssues prints issues as file:line: severity: path: message
+func outputValidationIssues(file string, issues []storage.ValidationIssue) {
+	if len(issues) == 0 {
+		fmt.Printf("%s: 문제가 없습니다.\n", file)
+		return
+	}
+
+	errors := 0
+	for _, issue := range issues {
+		location := file
+		if issue.Line > 0 {
+			location = fmt.Sprintf("%s:%d", file, issue.Line)
+		}
+		label := "경고"
+		if issue.Severity == storage.SeverityError {
+			label = "오류"
+			errors++
+		}
+		if issue.Path != "" {
+			fmt.Printf("%s: %s: %s: %s\n", location, label, issue.Path, issue.Message)
+		} else {
+			fmt.Printf("%s: %s: %s\n", location, label, issue.Message)
+		}
+	}
+	fmt.Printf("\n오류 %d건, 경고 %d건\n", errors, len(issues)-errors)
+}
+
+// parseOptionalTime parses a catalog date; an empty value means now
+func parseOptionalTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return storage.ParseEffectiveTime(value)
+}
+
+func outputPriceDiffs(diffs []storage.PriceDiff) error {
+	if len(diffs) == 0 {
+		fmt.Println("가격 변경 사항이 없습니다.")
+		return nil
+	}
+
+	fmt.Printf("=== 가격 변경 사항 (%d건) ===\n", len(diffs))
+	for _, diff := range diffs {
+		entry := diff.Entry
+		name := fmt.Sprintf("%s (%s)", entry.FlavorName, entry.FlavorID)
+		if entry.Region != "" {
+			name = fmt.Sprintf("%s [%s]", name, entry.Region)
+		}
+		if entry.CSP != "" {
+			name = entry.CSP + " " + name
+		}
+
+		switch diff.Kind {
+		case storage.PriceDiffAdded:
+			fmt.Printf("+ %s: 시간당 %.2f %s 추가\n", name, entry.Pricing.Hourly, entry.Currency)
+		case storage.PriceDiffRemoved:
+			fmt.Printf("- %s: 시간당 %.2f %s 삭제\n", name, entry.Pricing.Hourly, entry.Currency)
+		case storage.PriceDiffChanged:
+			before := diff.Before.Pricing
+			fmt.Printf("~ %s (%s)\n", name, entry.Currency)
+			printPriceChange("시간당", before.Hourly, entry.Pricing.Hourly)
+			printPriceChange("월", before.Monthly, entry.Pricing.Monthly)
+			printPriceChange("연", before.Yearly, entry.Pricing.Yearly)
+			printOptionalPriceChange("정지 시간당", before.ShutdownHourly, entry.Pricing.ShutdownHourly)
+			printOptionalPriceChange("보관 시간당", before.StorageHourly, entry.Pricing.StorageHourly)
+		}
+	}
+	return nil
+}
+
+func printPriceChange(label string, before, after float64) {
+	if before == after {
+		return
+	}
+	if before > 0 {
+		fmt.Printf("    %s: %.2f → %.2f (%+.1f%%)\n", label, before, after, (after-before)/before*100)
+		return
+	}
+	fmt.Printf("    %s: %.2f → %.2f\n", label, before, after)
+}
+
+func printOptionalPriceChange(label string, before, after *float64) {
+	switch {
+	case before == nil && after == nil:
+	case before == nil:
+		fmt.Printf("    %s: 없음 → %.2f\n", label, *after)
+	case after == nil:
+		fmt.Printf("    %s: %.2f → 없음\n", label, *before)
+	default:
+		printPriceChange(label, *before, *after)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(pricingCmd)
+	pricingCmd.AddCommand(pricingDiffCmd)
+	pricingCmd.AddCommand(pricingValidateCmd)
+
+	pricingDiffCmd.Flags().StringVar(&pricingDiffFrom, "from", "", "비교 기준 시점 (YYYY-MM-DD 또는 RFC3339)")
+	pricingDiffCmd.Flags().StringVar(&pricingDiffTo, "to", "", "비교 대상 시점 (기본값: 현재)")
+	pricingDiffCmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "출력 형식 (table, json)")
+
+	pricingValidateCmd.Flags().BoolVar(&pricingValidateStrict, "strict", false, "경고도 오류로 처리")
+	pricingValidateCmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "출력 형식 (table, json)")
+}
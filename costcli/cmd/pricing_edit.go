@@ -0,0 +1,463 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"costcli/pkg/storage"
+	"costconfig/config"
+)
+
+var (
+	pricingFile        string
+	pricingCSP         string
+	pricingCurrency    string
+	pricingEffective   string
+	pricingRegion      string
+	pricingGlobal      bool
+	pricingRegions     []string
+	pricingPrices      pricingPriceFlags
+	pricingFlavorFlags storage.InstanceType
+)
+
+// pricingPriceFlags holds the price flags shared by set-price and add-flavor
+type pricingPriceFlags struct {
+	hourly, monthly, yearly, shutdownHourly, storageHourly float64
+}
+
+var pricingListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "flavor 가격 목록",
+	Long:  `가격 파일에 등록된 flavor와 기본 통화 가격을 보여줍니다.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pricing, err := loadPricing()
+		if err != nil {
+			return err
+		}
+
+		entries := pricing.CatalogAt(time.Time{})
+		if pricingCSP != "" {
+			filtered := entries[:0]
+			for _, entry := range entries {
+				if entry.CSP == pricingCSP {
+					filtered = append(filtered, entry)
+				}
+			}
+			entries = filtered
+		}
+
+		switch outputFormat {
+		case "json":
+			return outputJSON(entries)
+		default:
+			return outputPricingList(pricing, entries)
+		}
+	},
+}
+
+var pricingShowCmd = &cobra.Command{
+	Use:   "show FLAVOR",
+	Short: "flavor 상세 정보",
+	Long:  `flavor ID 또는 이름으로 인스턴스 타입의 사양, 가격, 가격 이력을 보여줍니다.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		document, file, err := loadPricingDocument()
+		if err != nil {
+			return err
+		}
+		pricing, err := document.Storage()
+		if err != nil {
+			return fmt.Errorf("가격 정보 로딩 실패 (%s): %w", file, err)
+		}
+
+		if !pricing.IsNewFormat() {
+			price, exists := pricing.GetFlavorPrice(args[0])
+			if !exists {
+				return fmt.Errorf("flavor %s를 찾을 수 없습니다", args[0])
+			}
+			return outputJSON(price)
+		}
+
+		flavorID, err := document.ResolveFlavor(pricingCSP, args[0])
+		if err != nil {
+			return err
+		}
+		cspName := pricingCSP
+		if cspName == "" {
+			cspName = pricing.DefaultCSPName()
+		}
+		instanceType, _ := pricing.GetInstanceTypeInfo(cspName, flavorID)
+
+		switch outputFormat {
+		case "json":
+			return outputJSON(instanceType)
+		default:
+			outputInstanceType(cspName, instanceType)
+			return nil
+		}
+	},
+}
+
+var pricingSetPriceCmd = &cobra.Command{
+	Use:   "set-price FLAVOR",
+	Short: "flavor 가격 변경",
+	Long: `flavor의 가격을 변경합니다. 지정한 가격만 바뀌고 나머지는 유지됩니다.
+
+--effective-from을 지정하면 기존 가격을 price_history에 남겨 그 시점 이전 사용분은
+기존 가격으로 계산되도록 합니다. --region을 지정하면 해당 리전에만 적용되는 가격(region_pricing)을 변경합니다.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		update := pricingPrices.update(cmd)
+		if update == (storage.PriceUpdate{}) {
+			return fmt.Errorf("변경할 가격을 하나 이상 지정해야 합니다 (--hourly, --monthly, --yearly 등)")
+		}
+
+		return editPricing(func(document *storage.PricingDocument) (string, error) {
+			flavorID, err := document.ResolveFlavor(pricingCSP, args[0])
+			if err != nil {
+				return "", err
+			}
+			if err := document.SetPrice(pricingCSP, flavorID, pricingRegion, pricingCurrency, update, pricingEffective); err != nil {
+				return "", err
+			}
+			if pricingRegion != "" {
+				return fmt.Sprintf("flavor %s의 %s 리전 가격을 변경했습니다", flavorID, pricingRegion), nil
+			}
+			return fmt.Sprintf("flavor %s 가격을 변경했습니다", flavorID), nil
+		})
+	},
+}
+
+var pricingAddFlavorCmd = &cobra.Command{
+	Use:   "add-flavor ID",
+	Short: "flavor 추가",
+	Long:  `인스턴스 타입을 추가합니다. --hourly는 필수이며 통화를 지정하지 않으면 CSP 기본 통화를 사용합니다.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cmd.Flags().Changed("hourly") {
+			return fmt.Errorf("--hourly 값이 필요합니다")
+		}
+
+		return editPricing(func(document *storage.PricingDocument) (string, error) {
+			instanceType := pricingFlavorFlags
+			instanceType.ID = args[0]
+			if instanceType.Name == "" {
+				instanceType.Name = args[0]
+			}
+			instanceType.Availability = pricingRegions
+			if instanceType.Availability == nil {
+				instanceType.Availability = []string{}
+			}
+
+			if err := document.AddFlavor(pricingCSP, instanceType); err != nil {
+				return "", err
+			}
+			if err := document.SetPrice(pricingCSP, instanceType.ID, "", pricingCurrency, pricingPrices.update(cmd), ""); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("flavor %s를 추가했습니다", instanceType.ID), nil
+		})
+	},
+}
+
+var pricingRemoveFlavorCmd = &cobra.Command{
+	Use:   "remove-flavor FLAVOR",
+	Short: "flavor 삭제",
+	Long:  `인스턴스 타입과 가격을 가격 파일에서 삭제합니다.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editPricing(func(document *storage.PricingDocument) (string, error) {
+			flavorID, err := document.RemoveFlavor(pricingCSP, args[0])
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("flavor %s를 삭제했습니다", flavorID), nil
+		})
+	},
+}
+
+var pricingAddRuleCmd = &cobra.Command{
+	Use:   "add-rule [RULE_FILE]",
+	Short: "할인 규칙 추가",
+	Long: `JSON으로 작성한 할인 규칙을 추가합니다. 파일을 지정하지 않거나 '-'이면 표준 입력에서 읽습니다.
+--global을 지정하면 모든 CSP에 적용되는 global_discount_rules에 추가합니다.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var data []byte
+		var err error
+		if len(args) == 0 || args[0] == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("할인 규칙 읽기 실패: %w", err)
+		}
+
+		return editPricing(func(document *storage.PricingDocument) (string, error) {
+			location, err := document.AddRule(pricingCSP, pricingGlobal, data)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("할인 규칙을 %s에 추가했습니다", location), nil
+		})
+	},
+}
+
+var pricingEnableRuleCmd = &cobra.Command{
+	Use:   "enable-rule ID",
+	Short: "할인 규칙 활성화",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setRuleEnabled(args[0], true)
+	},
+}
+
+var pricingDisableRuleCmd = &cobra.Command{
+	Use:   "disable-rule ID",
+	Short: "할인 규칙 비활성화",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setRuleEnabled(args[0], false)
+	},
+}
+
+var pricingConvertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "기존 형식 가격 파일 변환",
+	Long: `기존 flavors 형식의 가격 파일을 새 형식(csps)으로 변환합니다.
+기존 형식의 정지 요금(90일간 시간당 요금의 10%)은 shutdown_billing으로 옮겨집니다.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editPricing(func(document *storage.PricingDocument) (string, error) {
+			cspName := pricingCSP
+			if cspName == "" {
+				cspName = "nhn"
+			}
+			if err := document.ConvertLegacy(cspName, pricingRegions); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("새 형식으로 변환했습니다 (CSP: %s)", cspName), nil
+		})
+	},
+}
+
+// update returns the prices whose flags were given
+func (f *pricingPriceFlags) update(cmd *cobra.Command) storage.PriceUpdate {
+	var update storage.PriceUpdate
+	flags := cmd.Flags()
+	if flags.Changed("hourly") {
+		update.Hourly = &f.hourly
+	}
+	if flags.Changed("monthly") {
+		update.Monthly = &f.monthly
+	}
+	if flags.Changed("yearly") {
+		update.Yearly = &f.yearly
+	}
+	if flags.Changed("shutdown-hourly") {
+		update.ShutdownHourly = &f.shutdownHourly
+	}
+	if flags.Changed("storage-hourly") {
+		update.StorageHourly = &f.storageHourly
+	}
+	return update
+}
+
+// pricingFilePath returns the --file flag or the configured pricing file
+func pricingFilePath() (string, error) {
+	if pricingFile != "" {
+		return pricingFile, nil
+	}
+	cfg, err := config.LoadConfig(env.ConfigPath)
+	if err != nil {
+		return "", fmt.Errorf("설정 로딩 실패: %w", err)
+	}
+	return cfg.Storage.PriceFile, nil
+}
+
+func loadPricing() (*storage.PricingStorage, error) {
+	file, err := pricingFilePath()
+	if err != nil {
+		return nil, err
+	}
+	pricing := storage.NewPricingStorage()
+	if err := pricing.LoadFromFile(file); err != nil {
+		return nil, fmt.Errorf("가격 정보 로딩 실패 (%s): %w", file, err)
+	}
+	return pricing, nil
+}
+
+func loadPricingDocument() (*storage.PricingDocument, string, error) {
+	file, err := pricingFilePath()
+	if err != nil {
+		return nil, "", err
+	}
+	document, err := storage.LoadPricingDocument(file)
+	if err != nil {
+		return nil, file, fmt.Errorf("가격 정보 로딩 실패 (%s): %w", file, err)
+	}
+	return document, file, nil
+}
+
+// editPricing loads the pricing file, applies an edit and saves it when the
+// edit introduces no validation errors
+func editPricing(edit func(document *storage.PricingDocument) (string, error)) error {
+	document, file, err := loadPricingDocument()
+	if err != nil {
+		return err
+	}
+
+	message, err := edit(document)
+	if err != nil {
+		return err
+	}
+
+	if err := document.SaveToFile(file); err != nil {
+		var validationErr *storage.PricingValidationError
+		if errors.As(err, &validationErr) {
+			for _, issue := range validationErr.Issues {
+				fmt.Fprintf(os.Stderr, "오류: %s: %s\n", issue.Path, issue.Message)
+			}
+			return fmt.Errorf("변경 사항이 가격 파일 검증을 통과하지 못해 저장하지 않았습니다")
+		}
+		return fmt.Errorf("가격 파일 저장 실패: %w", err)
+	}
+
+	fmt.Printf("%s: %s\n", message, file)
+	return nil
+}
+
+func setRuleEnabled(id string, enabled bool) error {
+	return editPricing(func(document *storage.PricingDocument) (string, error) {
+		location, err := document.SetRuleEnabled(id, enabled)
+		if err != nil {
+			return "", err
+		}
+		state := "비활성화"
+		if enabled {
+			state = "활성화"
+		}
+		return fmt.Sprintf("할인 규칙 %s (%s)를 %s했습니다", id, location, state), nil
+	})
+}
+
+func outputPricingList(pricing *storage.PricingStorage, entries []storage.CatalogEntry) error {
+	if len(entries) == 0 {
+		fmt.Println("등록된 flavor가 없습니다.")
+		return nil
+	}
+
+	fmt.Printf("%-8s %-40s %-20s %5s %9s %12s %14s %s\n", "CSP", "ID", "이름", "vCPU", "메모리(MB)", "시간당", "월", "통화")
+	for _, entry := range entries {
+		vcpu, memory := "-", "-"
+		if instanceType, exists := pricing.GetInstanceTypeInfo(entry.CSP, entry.FlavorID); exists {
+			vcpu, memory = fmt.Sprint(instanceType.VCPU), fmt.Sprint(instanceType.MemoryMB)
+		}
+		csp := entry.CSP
+		if csp == "" {
+			csp = "-"
+		}
+		fmt.Printf("%-8s %-40s %-20s %5s %9s %12.2f %14.2f %s\n", csp, entry.FlavorID, entry.FlavorName, vcpu, memory, entry.Pricing.Hourly, entry.Pricing.Monthly, entry.Currency)
+	}
+	return nil
+}
+
+func outputInstanceType(cspName string, instanceType *storage.InstanceType) {
+	fmt.Printf("=== %s (%s) ===\n", instanceType.Name, instanceType.ID)
+	fmt.Printf("CSP: %s\n", cspName)
+	if instanceType.DisplayName != "" {
+		fmt.Printf("표시 이름: %s\n", instanceType.DisplayName)
+	}
+	fmt.Printf("사양: vCPU %d, 메모리 %dMB, 디스크 %dGB\n", instanceType.VCPU, instanceType.MemoryMB, instanceType.DiskGB)
+	if len(instanceType.Availability) > 0 {
+		fmt.Printf("리전: %s\n", strings.Join(instanceType.Availability, ", "))
+	}
+	if instanceType.Contract != "" {
+		fmt.Printf("약정: %s\n", instanceType.Contract)
+	}
+
+	fmt.Printf("가격:\n")
+	printPricingMap(instanceType.Pricing)
+	printRegionPricing(instanceType.RegionPricing)
+
+	for _, version := range instanceType.PriceHistory {
+		fmt.Printf("이전 가격 (%s 이전):\n", version.EffectiveTo)
+		printPricingMap(version.Pricing)
+		printRegionPricing(version.RegionPricing)
+	}
+}
+
+func printRegionPricing(regionPricing map[string]map[string]storage.Pricing) {
+	regions := make([]string, 0, len(regionPricing))
+	for region := range regionPricing {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	for _, region := range regions {
+		fmt.Printf("  [%s 리전]\n", region)
+		printPricingMap(regionPricing[region])
+	}
+}
+
+func printPricingMap(pricing map[string]storage.Pricing) {
+	currencies := make([]string, 0, len(pricing))
+	for currency := range pricing {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	for _, currency := range currencies {
+		price := pricing[currency]
+		fmt.Printf("  - %s: 시간당 %.2f, 월 %.2f, 연 %.2f", currency, price.Hourly, price.Monthly, price.Yearly)
+		if price.ShutdownHourly != nil {
+			fmt.Printf(", 정지 시간당 %.2f", *price.ShutdownHourly)
+		}
+		if price.StorageHourly != nil {
+			fmt.Printf(", 보관 시간당 %.2f", *price.StorageHourly)
+		}
+		fmt.Println()
+	}
+}
+
+func init() {
+	pricingCmd.AddCommand(pricingListCmd, pricingShowCmd, pricingSetPriceCmd, pricingAddFlavorCmd, pricingRemoveFlavorCmd,
+		pricingAddRuleCmd, pricingEnableRuleCmd, pricingDisableRuleCmd, pricingConvertCmd)
+
+	pricingCmd.PersistentFlags().StringVarP(&pricingFile, "file", "f", "", "가격 파일 경로 (기본값: 설정의 price_file)")
+	for _, cmd := range []*cobra.Command{pricingListCmd, pricingShowCmd, pricingSetPriceCmd, pricingAddFlavorCmd, pricingRemoveFlavorCmd, pricingAddRuleCmd, pricingConvertCmd} {
+		cmd.Flags().StringVar(&pricingCSP, "csp", "", "CSP 이름 (기본값: default_csp)")
+	}
+	pricingListCmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "출력 형식 (table, json)")
+	pricingShowCmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "출력 형식 (table, json)")
+
+	for _, cmd := range []*cobra.Command{pricingSetPriceCmd, pricingAddFlavorCmd} {
+		cmd.Flags().Float64Var(&pricingPrices.hourly, "hourly", 0, "시간당 요금")
+		cmd.Flags().Float64Var(&pricingPrices.monthly, "monthly", 0, "월 요금")
+		cmd.Flags().Float64Var(&pricingPrices.yearly, "yearly", 0, "연 요금")
+		cmd.Flags().Float64Var(&pricingPrices.shutdownHourly, "shutdown-hourly", 0, "정지 시간당 요금")
+		cmd.Flags().Float64Var(&pricingPrices.storageHourly, "storage-hourly", 0, "보관(storage_only) 시간당 요금")
+		cmd.Flags().StringVar(&pricingCurrency, "currency", "", "가격 통화 (기본값: CSP 기본 통화)")
+	}
+	pricingSetPriceCmd.Flags().StringVar(&pricingEffective, "effective-from", "", "새 가격 적용 시점 (YYYY-MM-DD 또는 RFC3339, 이전 가격은 이력에 보관)")
+	pricingSetPriceCmd.Flags().StringVar(&pricingRegion, "region", "", "리전별 가격으로 변경할 리전 (기본값: 공통 가격)")
+
+	pricingAddFlavorCmd.Flags().StringVar(&pricingFlavorFlags.Name, "name", "", "flavor 이름 (기본값: ID)")
+	pricingAddFlavorCmd.Flags().StringVar(&pricingFlavorFlags.DisplayName, "display-name", "", "표시 이름")
+	pricingAddFlavorCmd.Flags().IntVar(&pricingFlavorFlags.VCPU, "vcpu", 0, "vCPU 수")
+	pricingAddFlavorCmd.Flags().IntVar(&pricingFlavorFlags.MemoryMB, "memory-mb", 0, "메모리 (MB)")
+	pricingAddFlavorCmd.Flags().IntVar(&pricingFlavorFlags.DiskGB, "disk-gb", 0, "디스크 (GB)")
+	pricingAddFlavorCmd.Flags().StringVar(&pricingFlavorFlags.Contract, "contract", "", "약정 (monthly, yearly)")
+	pricingAddFlavorCmd.Flags().StringSliceVar(&pricingRegions, "region", nil, "사용 가능 리전 (여러 번 지정 가능)")
+
+	pricingAddRuleCmd.Flags().BoolVar(&pricingGlobal, "global", false, "모든 CSP에 적용되는 전역 규칙으로 추가")
+
+	pricingConvertCmd.Flags().StringSliceVar(&pricingRegions, "region", nil, "변환된 flavor의 사용 가능 리전 (여러 번 지정 가능)")
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+
+	"costconfig/configcmd"
 )
 
 var rootCmd = &cobra.Command{
@@ -15,12 +17,18 @@ var rootCmd = &cobra.Command{
 		fmt.Println("사용법: costcli [command]")
 		cmd.Help()
 	},
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return env.ResolveConfigPath(cmd)
+	},
 }
 
+// env holds the config file and profile selected by --config and --profile
+var env = configcmd.NewEnv("costcli")
+
 func Execute() error {
 	return rootCmd.Execute()
 }
 
 func init() {
-	// 설정 파일 및 플래그 초기화
+	env.AddFlags(rootCmd)
 }
\ No newline at end of file
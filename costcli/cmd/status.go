@@ -3,10 +3,11 @@ package cmd
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
-	"costcli/pkg/config"
 	"costcli/pkg/storage"
+	"costconfig/config"
 	"github.com/spf13/cobra"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -17,7 +18,7 @@ var statusCmd = &cobra.Command{
 	Short: "인스턴스 상태 조회",
 	Long:  `저장된 인스턴스 상태 정보를 조회합니다.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.LoadConfig(configPath)
+		cfg, err := config.LoadConfig(env.ConfigPath)
 		if err != nil {
 			return fmt.Errorf("설정 로딩 실패: %w", err)
 		}
@@ -29,16 +30,22 @@ var statusCmd = &cobra.Command{
 
 		instances := stateStorage.GetAllInstances()
 
+		// 가격 파일이 없어도 기본 상태별 과금 구분으로 조회
+		pricingStorage := storage.NewPricingStorage()
+		if err := pricingStorage.LoadFromFile(cfg.Storage.PriceFile); err != nil {
+			pricingStorage = storage.NewPricingStorage()
+		}
+
 		switch outputFormat {
 		case "json":
 			return outputJSON(instances)
 		default:
-			return outputInstanceStatus(instances, stateStorage.LastUpdate)
+			return outputInstanceStatus(instances, stateStorage.LastUpdate, pricingStorage)
 		}
 	},
 }
 
-func outputInstanceStatus(instancesMap map[string]*storage.InstanceState, lastUpdate time.Time) error {
+func outputInstanceStatus(instancesMap map[string]*storage.InstanceState, lastUpdate time.Time, pricingStorage *storage.PricingStorage) error {
 	kst, _ := time.LoadLocation("Asia/Seoul")
 	p := message.NewPrinter(language.Korean)
 
@@ -58,27 +65,92 @@ func outputInstanceStatus(instancesMap map[string]*storage.InstanceState, lastUp
 	fmt.Printf("총 인스턴스: %d개\n\n", len(instances))
 
 	for _, instance := range instances {
-		status := "UNKNOWN"
-		if instance.CurrentStatus == "ACTIVE" && instance.CurrentPowerState == 1 {
-			status = "RUNNING"
-		} else if instance.CurrentStatus == "SHUTOFF" || instance.CurrentPowerState == 4 {
-			status = "SHUTDOWN"
+		status := storage.EffectiveStatus(instance.CurrentStatus, instance.CurrentPowerState)
+		cspName := instance.CSP
+		if cspName == "" {
+			cspName = pricingStorage.DefaultCSPName()
 		}
+		billingClass := pricingStorage.GetBillingClass(cspName, instance.CurrentStatus, instance.CurrentPowerState)
 
 		fmt.Printf("인스턴스: %s (%s)\n", instance.Name, instance.ID)
-		fmt.Printf("  - 상태: %s\n", status)
+		fmt.Printf("  - 상태: %s (과금: %s)\n", status, billingClassLabel(billingClass))
+		if len(pricingStorage.CSPNames()) > 1 {
+			fmt.Printf("  - CSP: %s\n", cspName)
+		}
 		fmt.Printf("  - Flavor: %s\n", instance.FlavorID)
+		if len(instance.FlavorHistory) > 1 {
+			fmt.Printf("  - Flavor 변경 이력:\n")
+			for _, change := range instance.FlavorHistory {
+				fmt.Printf("    * %s부터 %s\n", change.Since.In(kst).Format("2006-01-02 15:04"), change.FlavorID)
+			}
+		}
 		fmt.Printf("  - 생성: %s\n", instance.CreatedAt.In(kst).Format("2006-01-02 15:04"))
 		fmt.Printf("  - 마지막 업데이트: %s\n", instance.LastUpdated.In(kst).Format("2006-01-02 15:04"))
-		p.Printf("  - 총 실행 시간: %d분\n", instance.GetTotalRunningMinutes())
-		p.Printf("  - 총 정지 시간: %d분\n", instance.GetTotalShutdownMinutes())
+		if instance.DeletedAt != nil {
+			source := "Nova 삭제 기록"
+			switch instance.DeletedAtSource {
+			case storage.DeletedAtSourceAPI:
+				source = "CSP API 삭제 기록"
+			case storage.DeletedAtSourceDetected:
+				source = "목록에서 사라진 것을 감지한 시각"
+			}
+			fmt.Printf("  - 삭제: %s (%s)\n", instance.DeletedAt.In(kst).Format("2006-01-02 15:04"), source)
+		}
+
+		// 총 시간은 삭제 시각에서 끝나는 상태 구간을 과금 구분별로 합산
+		durations := instance.GetStatusDurations()
+		statuses := make([]string, 0, len(durations))
+		classTotals := make(map[string]time.Duration)
+		for name, duration := range durations {
+			statuses = append(statuses, name)
+			classTotals[pricingStorage.GetBillingClass(cspName, name, 0)] += duration
+		}
+		sort.Strings(statuses)
+
+		stopped := classTotals[storage.BillingClassReduced] + classTotals[storage.BillingClassStorageOnly] + classTotals[storage.BillingClassFree]
+		p.Printf("  - 총 실행 시간: %d분\n", int(classTotals[storage.BillingClassFull].Minutes()))
+		p.Printf("  - 총 정지 시간: %d분", int(stopped.Minutes()))
+		if stopped > 0 {
+			var parts []string
+			for _, class := range []string{storage.BillingClassReduced, storage.BillingClassStorageOnly, storage.BillingClassFree} {
+				if classTotals[class] > 0 {
+					parts = append(parts, p.Sprintf("%s %d분", billingClassLabel(class), int(classTotals[class].Minutes())))
+				}
+			}
+			fmt.Printf(" (%s)", strings.Join(parts, ", "))
+		}
+		fmt.Println()
 		fmt.Printf("  - 현재 상태 지속시간: %s\n", instance.GetCurrentStateDuration().Truncate(time.Second).String())
+
+		if len(statuses) > 0 {
+			fmt.Printf("  - 상태별 시간:\n")
+			for _, name := range statuses {
+				class := pricingStorage.GetBillingClass(cspName, name, 0)
+				p.Printf("    * %s: %d분 (%s)\n", name, int(durations[name].Minutes()), billingClassLabel(class))
+			}
+		}
 		fmt.Println()
 	}
 
 	return nil
 }
 
+// billingClassLabel describes how time in a status is charged
+func billingClassLabel(class string) string {
+	switch class {
+	case storage.BillingClassFull:
+		return "전액"
+	case storage.BillingClassReduced:
+		return "정지 요금"
+	case storage.BillingClassStorageOnly:
+		return "스토리지 요금"
+	case storage.BillingClassFree:
+		return "무료"
+	default:
+		return class
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(statusCmd)
 
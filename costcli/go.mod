@@ -11,3 +11,7 @@ require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.6 // indirect
 )
+
+require costconfig v0.0.0
+
+replace costconfig => ../costconfig
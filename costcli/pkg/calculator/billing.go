@@ -0,0 +1,286 @@
+package calculator
+
+import (
+	"time"
+
+	"costcli/pkg/money"
+	"costcli/pkg/storage"
+)
+
+// calculateBaseCost returns the pre-discount cost of an instance and the
+// billing model used. Contracted flavors or projects are charged the flat
+// monthly/yearly price pro-rated over the period; otherwise running hours are
+// charged hourly, optionally capped at the monthly price per billing month.
+// since is when the instance started using the priced flavor.
+func (c *CostCalculator) calculateBaseCost(instance *storage.InstanceState, price *storage.FlavorPrice, runningHours float64, startTime, endTime, since time.Time) (money.Money, string) {
+	billingModel, contract := c.pricingStorage.GetBillingTerms(c.cspName(), price.FlavorID)
+
+	hourly := money.FromFloat(price.HourlyPrice, price.Currency)
+	monthly := money.FromFloat(price.MonthlyPrice, price.Currency)
+	yearly := money.FromFloat(price.YearlyPrice, price.Currency)
+
+	switch {
+	case contract == storage.ContractMonthly && monthly.IsPositive():
+		start, end := c.contractPeriod(instance, startTime, endTime)
+		return monthly.Mul(monthFraction(start, end)), contract
+
+	case contract == storage.ContractYearly && yearly.IsPositive():
+		start, end := c.contractPeriod(instance, startTime, endTime)
+		return yearly.Mul(yearFraction(start, end)), contract
+
+	case billingModel == storage.BillingModelMonthlyCap && monthly.IsPositive():
+		return c.monthlyCappedCost(instance, hourly, monthly, startTime, endTime, since), billingModel
+
+	default:
+		return hourly.Mul(runningHours), storage.BillingModelHourly
+	}
+}
+
+// stoppedLineItems charges the hours spent in reduced-billing states such as
+// SHUTOFF. Stopped hours within the eligibility window after creation use the reduced
+// shutdown rate; later ones use the after-window rate. The minimum billable
+// duration only applies to running segments.
+func (c *CostCalculator) stoppedLineItems(instance *storage.InstanceState, price *storage.FlavorPrice, startTime, endTime time.Time) []CostLineItem {
+	billing, exists := c.pricingStorage.GetShutdownBilling(c.cspName())
+	if !exists {
+		return nil
+	}
+
+	hourly := money.FromFloat(price.HourlyPrice, price.Currency)
+	reducedRate := hourly.Mul(billing.RatePercent / 100.0)
+	if price.ShutdownHourlyPrice != nil {
+		reducedRate = money.FromFloat(*price.ShutdownHourlyPrice, price.Currency)
+	}
+
+	windowEnd := endTime
+	if billing.EligibleDays > 0 {
+		windowEnd = instance.CreatedAt.AddDate(0, 0, billing.EligibleDays)
+	}
+
+	var items []CostLineItem
+	addItem := func(itemType string, rate money.Money, from, to time.Time) {
+		if !to.After(from) {
+			return
+		}
+		hours := c.meteredHours(c.stateSegments(instance, from, to, storage.BillingClassReduced), 0)
+		if hours <= 0 {
+			return
+		}
+		items = append(items, CostLineItem{
+			Type:       itemType,
+			Hours:      hours,
+			HourlyRate: rate,
+			Amount:     c.roundAmount(rate.Mul(hours)),
+		})
+	}
+
+	reducedEnd := endTime
+	if windowEnd.Before(reducedEnd) {
+		reducedEnd = windowEnd
+	}
+	afterStart := startTime
+	if windowEnd.After(afterStart) {
+		afterStart = windowEnd
+	}
+
+	addItem(LineItemStopped, reducedRate, startTime, reducedEnd)
+	addItem(LineItemStoppedAfterWindow, hourly.Mul(billing.AfterWindowRatePercent()/100.0), afterStart, endTime)
+
+	return items
+}
+
+// storageLineItems charges the hours spent in storage-only states such as
+// SHELVED_OFFLOADED at the flavor's storage price. Without a storage price
+// those hours are free.
+func (c *CostCalculator) storageLineItems(instance *storage.InstanceState, price *storage.FlavorPrice, startTime, endTime time.Time) []CostLineItem {
+	if price.StorageHourlyPrice == nil {
+		return nil
+	}
+
+	hours := c.meteredHours(c.stateSegments(instance, startTime, endTime, storage.BillingClassStorageOnly), 0)
+	if hours <= 0 {
+		return nil
+	}
+
+	rate := money.FromFloat(*price.StorageHourlyPrice, price.Currency)
+	return []CostLineItem{{
+		Type:       LineItemStorage,
+		Hours:      hours,
+		HourlyRate: rate,
+		Amount:     c.roundAmount(rate.Mul(hours)),
+	}}
+}
+
+// billingClass returns how a status is charged by the default CSP
+func (c *CostCalculator) billingClass(status string, powerState int) string {
+	return c.pricingStorage.GetBillingClass(c.cspName(), status, powerState)
+}
+
+// contractPeriod clips the calculation period to the instance lifetime, since
+// a contracted instance is charged whether it runs or not.
+func (c *CostCalculator) contractPeriod(instance *storage.InstanceState, startTime, endTime time.Time) (time.Time, time.Time) {
+	start := startTime
+	if instance.CreatedAt.After(start) {
+		start = instance.CreatedAt
+	}
+	end := endTime
+	if instance.DeletedAt != nil && instance.DeletedAt.Before(end) {
+		end = *instance.DeletedAt
+	}
+	if start.After(end) {
+		return end, end
+	}
+	return start, end
+}
+
+// monthlyCappedCost charges running hours hourly but never more than the
+// monthly price within one calendar month. Hours already run earlier in a
+// month, before the calculation period, count towards that month's cap,
+// as long as they ran on the same flavor (since onwards).
+func (c *CostCalculator) monthlyCappedCost(instance *storage.InstanceState, hourly, monthly money.Money, startTime, endTime, since time.Time) money.Money {
+	total := money.Zero(hourly.Currency())
+
+	monthStart := time.Date(startTime.Year(), startTime.Month(), 1, 0, 0, 0, 0, startTime.Location())
+	for monthStart.Before(endTime) {
+		monthEnd := monthStart.AddDate(0, 1, 0)
+
+		segmentStart := monthStart
+		if startTime.After(segmentStart) {
+			segmentStart = startTime
+		}
+		segmentEnd := monthEnd
+		if endTime.Before(segmentEnd) {
+			segmentEnd = endTime
+		}
+
+		hoursBefore := 0.0
+		capStart := monthStart
+		if since.After(capStart) {
+			capStart = since
+		}
+		if segmentStart.After(capStart) {
+			hoursBefore = c.calculateRunningHours(instance, capStart, segmentStart)
+		}
+		hoursIn := c.calculateRunningHours(instance, segmentStart, segmentEnd)
+
+		chargedBefore := money.Min(hourly.Mul(hoursBefore), monthly)
+		chargedThrough := money.Min(hourly.Mul(hoursBefore+hoursIn), monthly)
+		total = total.Add(chargedThrough.Sub(chargedBefore))
+
+		monthStart = monthEnd
+	}
+
+	return total
+}
+
+// monthFraction returns how many calendar months the period covers, e.g.
+// 0.5 for the first half of a 30-day month.
+func monthFraction(startTime, endTime time.Time) float64 {
+	fraction := 0.0
+	monthStart := time.Date(startTime.Year(), startTime.Month(), 1, 0, 0, 0, 0, startTime.Location())
+	for monthStart.Before(endTime) {
+		monthEnd := monthStart.AddDate(0, 1, 0)
+
+		overlapStart := monthStart
+		if startTime.After(overlapStart) {
+			overlapStart = startTime
+		}
+		overlapEnd := monthEnd
+		if endTime.Before(overlapEnd) {
+			overlapEnd = endTime
+		}
+		if overlapEnd.After(overlapStart) {
+			fraction += overlapEnd.Sub(overlapStart).Hours() / monthEnd.Sub(monthStart).Hours()
+		}
+
+		monthStart = monthEnd
+	}
+	return fraction
+}
+
+// yearFraction returns how many calendar years the period covers
+func yearFraction(startTime, endTime time.Time) float64 {
+	fraction := 0.0
+	yearStart := time.Date(startTime.Year(), 1, 1, 0, 0, 0, 0, startTime.Location())
+	for yearStart.Before(endTime) {
+		yearEnd := yearStart.AddDate(1, 0, 0)
+
+		overlapStart := yearStart
+		if startTime.After(overlapStart) {
+			overlapStart = startTime
+		}
+		overlapEnd := yearEnd
+		if endTime.Before(overlapEnd) {
+			overlapEnd = endTime
+		}
+		if overlapEnd.After(overlapStart) {
+			fraction += overlapEnd.Sub(overlapStart).Hours() / yearEnd.Sub(yearStart).Hours()
+		}
+
+		yearStart = yearEnd
+	}
+	return fraction
+}
+
+// billingRules returns the billing rules of the default CSP
+func (c *CostCalculator) billingRules() storage.BillingRules {
+	if c.pricingStorage.NewPricingSchema == nil {
+		return storage.BillingRules{}
+	}
+	return c.pricingStorage.CSPs[c.cspName()].BillingRules
+}
+
+// billableHours sums the segments after applying the minimum billable
+// duration and the billing granularity to each run.
+func (c *CostCalculator) billableHours(segments []timeSegment) float64 {
+	return c.meteredHours(segments, time.Duration(c.billingRules().MinimumBillableSeconds)*time.Second)
+}
+
+// meteredHours sums the segments, raising each run to minimum and rounding
+// it up to the billing granularity. A run split by the report period, a
+// flavor or price change, or a day of the credit replay is metered once: the
+// extra time is added to the segment that ends the run, so the parts always
+// add up to the metered run.
+func (c *CostCalculator) meteredHours(segments []timeSegment, minimum time.Duration) float64 {
+	rules := c.billingRules()
+
+	var unit time.Duration
+	switch rules.Granularity {
+	case storage.GranularitySecond:
+		unit = time.Second
+	case storage.GranularityMinute:
+		unit = time.Minute
+	case storage.GranularityHour:
+		unit = time.Hour
+	}
+
+	total := time.Duration(0)
+	for _, segment := range segments {
+		total += segment.End.Sub(segment.Start)
+		if !segment.End.Equal(segment.RunEnd) {
+			continue
+		}
+
+		run := segment.RunEnd.Sub(segment.RunStart)
+		metered := run
+		if metered < minimum {
+			metered = minimum
+		}
+		if unit > 0 && metered%unit != 0 {
+			metered = (metered/unit + 1) * unit
+		}
+		total += metered - run
+	}
+
+	return total.Hours()
+}
+
+// roundAmount rounds an amount using the rounding rule configured for its
+// currency, or the currency's usual minor unit (KRW 0, USD 2 decimals).
+func (c *CostCalculator) roundAmount(amount money.Money) money.Money {
+	rule, exists := c.billingRules().Rounding[amount.Currency()]
+	if !exists {
+		return amount.Round(money.DefaultDecimals(amount.Currency()), money.RoundHalfUp)
+	}
+	return amount.Round(rule.Decimals, rule.Mode)
+}
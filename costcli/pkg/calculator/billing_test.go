@@ -0,0 +1,108 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"costcli/pkg/money"
+	"costcli/pkg/storage"
+)
+
+// newSchemaCalculator returns a calculator for a single KRW CSP named nhn
+func newSchemaCalculator(csp storage.CSPProvider, settings storage.GlobalSettings) *CostCalculator {
+	csp.Name = "nhn"
+	csp.DefaultCurrency = "KRW"
+	pricingStorage := storage.NewPricingStorage()
+	pricingStorage.NewPricingSchema = &storage.NewPricingSchema{
+		DefaultCSP:     "nhn",
+		CSPs:           map[string]storage.CSPProvider{"nhn": csp},
+		GlobalSettings: settings,
+	}
+	return NewCostCalculator(pricingStorage)
+}
+
+func TestMeteredHours(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 8, 10, hour, minute, 0, 0, time.UTC)
+	}
+	run := func(start, end time.Time) timeSegment {
+		return timeSegment{Start: start, End: end, RunStart: start, RunEnd: end}
+	}
+
+	tests := []struct {
+		name        string
+		granularity string
+		minimum     time.Duration
+		segments    []timeSegment
+		want        float64
+	}{
+		{"no granularity is exact", "", 0, []timeSegment{run(at(10, 0), at(10, 20))}, 20.0 / 60},
+		{"second granularity", storage.GranularitySecond, 0, []timeSegment{run(at(10, 0), at(10, 20))}, 20.0 / 60},
+		{"minute granularity rounds up", storage.GranularityMinute, 0, []timeSegment{run(at(10, 0), at(10, 0).Add(90*time.Second))}, 2.0 / 60},
+		{"hour granularity rounds up", storage.GranularityHour, 0, []timeSegment{run(at(10, 0), at(11, 20))}, 2},
+		{"minimum raises a short run", storage.GranularitySecond, time.Hour, []timeSegment{run(at(10, 0), at(10, 20))}, 1},
+		{"minimum does not lower a long run", storage.GranularitySecond, time.Hour, []timeSegment{run(at(10, 0), at(12, 0))}, 2},
+		{"each run is rounded", storage.GranularityHour, 0, []timeSegment{run(at(10, 0), at(10, 20)), run(at(12, 0), at(12, 20))}, 2},
+		{
+			"run cut before its end is not rounded", storage.GranularityHour, 0,
+			[]timeSegment{{Start: at(10, 0), End: at(12, 0), RunStart: at(10, 0), RunEnd: at(12, 30)}},
+			2,
+		},
+		{
+			"segment ending the run carries the rounding", storage.GranularityHour, 0,
+			[]timeSegment{{Start: at(12, 0), End: at(12, 30), RunStart: at(10, 0), RunEnd: at(12, 30)}},
+			1,
+		},
+		{
+			"segment ending the run carries the minimum", storage.GranularitySecond, 3 * time.Hour,
+			[]timeSegment{{Start: at(12, 0), End: at(12, 30), RunStart: at(10, 0), RunEnd: at(12, 30)}},
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calc := newSchemaCalculator(storage.CSPProvider{BillingRules: storage.BillingRules{Granularity: tt.granularity}}, storage.GlobalSettings{})
+			if got := calc.meteredHours(tt.segments, tt.minimum); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("meteredHours = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonthlyCappedCost(t *testing.T) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	// 7월 20일부터 8월 20일까지 계속 실행. 시간당 100원, 월 30000원 (300시간)
+	instance := &storage.InstanceState{
+		ID: "vm-1", FlavorID: "f1", CurrentStatus: storage.StatusActive, CurrentPowerState: storage.PowerStateRunning,
+		CreatedAt: date(7, 20), LastUpdated: date(7, 20), LastSeen: date(8, 20),
+	}
+	hourly, monthly := money.FromFloat(100, "KRW"), money.FromFloat(30000, "KRW")
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		since      time.Time
+		want       float64
+	}{
+		{"within one month under the cap", date(7, 20), date(7, 25), date(7, 20), 12000},
+		{"whole months", date(7, 1), date(9, 1), date(7, 20), 28800 + 30000},
+		{"across the month boundary", date(7, 25), date(8, 5), date(7, 20), 16800 + 9600},
+		{"earlier hours of the month count towards the cap", date(8, 10), date(8, 20), date(7, 20), 30000 - 21600},
+		{"cap is reached before the period", date(8, 15), date(8, 20), date(7, 20), 0},
+		{"hours before a flavor change do not count", date(8, 10), date(8, 20), date(8, 5), 30000 - 12000},
+	}
+
+	calc := newSchemaCalculator(storage.CSPProvider{}, storage.GlobalSettings{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calc.monthlyCappedCost(instance, hourly, monthly, tt.start, tt.end, tt.since)
+			if want := money.FromFloat(tt.want, "KRW"); got.Cmp(want) != 0 {
+				t.Errorf("monthlyCappedCost = %s, want %s", got, want)
+			}
+		})
+	}
+}
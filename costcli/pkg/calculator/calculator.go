@@ -1,24 +1,49 @@
 package calculator
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
+	"costcli/pkg/money"
 	"costcli/pkg/storage"
 )
 
 type CostCalculator struct {
 	pricingStorage *storage.PricingStorage
+	flavorStorage  *storage.FlavorStorage
+	currency       string
+	strict         bool
+	csp            string // 인스턴스별 계산 시 적용할 CSP (비어 있으면 default_csp)
+	// matches 조건의 컴파일된 정규식 (잘못된 패턴은 nil)
+	patterns map[string]*regexp.Regexp
 }
 
 type CostSummary struct {
 	Period           TimePeriod      `json:"period"`
 	TotalInstances   int             `json:"total_instances"`
-	TotalBaseCost    float64         `json:"total_base_cost"`
-	TotalDiscount    float64         `json:"total_discount"`
-	TotalFinalCost   float64         `json:"total_final_cost"`
+	TotalBaseCost    money.Money     `json:"total_base_cost"`
+	TotalDiscount    money.Money     `json:"total_discount"`
+	TotalFinalCost   money.Money     `json:"total_final_cost"`
 	Currency         string          `json:"currency"`
+	ExchangeRates    []storage.ExchangeRate `json:"exchange_rates,omitempty"`
 	InstanceCosts    []InstanceCost  `json:"instance_costs"`
+
+	// 프로젝트 합계에 적용된 할인 (scope: summary)
+	SummaryDiscounts        []DiscountDetail `json:"summary_discounts,omitempty"`
+	SkippedSummaryDiscounts []DiscountDetail `json:"skipped_summary_discounts,omitempty"`
+	TotalSummaryDiscount    money.Money      `json:"total_summary_discount,omitzero"`
+
+	// CSP별 소계 (보고 통화 기준)
+	CSPSubtotals []CSPSubtotal `json:"csp_subtotals,omitempty"`
+
+	// 가격 정보가 없어 합계에서 제외된 인스턴스
+	Unpriced []UnpricedInstance `json:"unpriced,omitempty"`
+	Warnings []string           `json:"warnings,omitempty"`
 }
 
 type TimePeriod struct {
@@ -29,51 +54,182 @@ type TimePeriod struct {
 type InstanceCost struct {
 	InstanceID          string            `json:"instance_id"`
 	InstanceName        string            `json:"instance_name"`
+	CSP                 string            `json:"csp,omitempty"`
+	DeletedAt           *time.Time        `json:"deleted_at,omitempty"`
 	FlavorID            string            `json:"flavor_id"`
 	FlavorName          string            `json:"flavor_name"`
-	BaseHourlyRate      float64           `json:"base_hourly_rate"`
+	Currency            string            `json:"currency"`
+	PriceSource         string            `json:"price_source,omitempty"`
+	EstimatedPrice      bool              `json:"estimated_price,omitempty"` // vCPU/메모리 단가로 추정한 가격
+	Region              string            `json:"region,omitempty"`
+	// 인스턴스 리전의 availability에 없는 flavor
+	UnavailableFlavors  []string          `json:"unavailable_flavors,omitempty"`
+	ExchangeRate        float64           `json:"exchange_rate,omitempty"`
+	BaseHourlyRate      money.Money       `json:"base_hourly_rate"`
 	TotalRunningHours   float64           `json:"total_running_hours"`
-	BaseCost            float64           `json:"base_cost"`
-	TotalDiscount       float64           `json:"total_discount"`
-	FinalCost           float64           `json:"final_cost"`
+	TotalStoppedHours   float64           `json:"total_stopped_hours,omitempty"`
+	LineItems           []CostLineItem    `json:"line_items,omitempty"`
+	BaseCost            money.Money       `json:"base_cost"`
+	BillingModel        string            `json:"billing_model,omitempty"`
+	TotalDiscount       money.Money       `json:"total_discount"`
+	FinalCost           money.Money       `json:"final_cost"`
 	AppliedDiscounts    []DiscountDetail  `json:"applied_discounts"`
+	SkippedDiscounts    []DiscountDetail  `json:"skipped_discounts,omitempty"`
 }
 
+// CostLineItem is one billed component of an instance's base cost
+type CostLineItem struct {
+	Type       string      `json:"type"`
+	Hours      float64     `json:"hours"`
+	HourlyRate money.Money `json:"hourly_rate"`
+	Amount     money.Money `json:"amount"`
+	FlavorID   string      `json:"flavor_id,omitempty"`   // 리사이즈된 경우 해당 구간의 flavor
+	FlavorName string      `json:"flavor_name,omitempty"`
+	// 리사이즈나 가격 변경으로 나뉜 경우 항목의 구간
+	Start time.Time `json:"start,omitzero"`
+	End   time.Time `json:"end,omitzero"`
+}
+
+// Line item types
+const (
+	LineItemRunning            = "running"              // 실행 시간 (과금 방식에 따른 기본 요금)
+	LineItemStopped            = "stopped"              // 감면 기간 내 정지 시간
+	LineItemStoppedAfterWindow = "stopped_after_window" // 감면 기간이 지난 정지 시간
+	LineItemStorage            = "storage"              // 디스크 보관 요금만 과금되는 시간 (SHELVED 등)
+)
+
 type DiscountDetail struct {
-	RuleName        string  `json:"rule_name"`
-	DiscountPercent float64 `json:"discount_percent"`
-	DiscountAmount  float64 `json:"discount_amount"`
+	RuleName        string    `json:"rule_name"`
+	DiscountPercent float64   `json:"discount_percent"`
+	DiscountAmount  money.Money `json:"discount_amount"`
+	EffectiveFrom   time.Time `json:"effective_from,omitzero"`
+	EffectiveTo     time.Time `json:"effective_to,omitzero"`
+	EffectiveRatio  float64   `json:"effective_ratio,omitempty"`
+	Priority        int       `json:"priority,omitempty"`
+	StackMode       string    `json:"stack_mode,omitempty"`
+	ExclusiveGroup  string    `json:"exclusive_group,omitempty"`
+	Resolution      string    `json:"resolution,omitempty"`
+	Method          string    `json:"method,omitempty"`
+	Scope           string    `json:"scope,omitempty"`
+	CSP             string    `json:"csp,omitempty"` // CSP 전용 요약 할인이면 해당 CSP
 }
 
 func NewCostCalculator(pricingStorage *storage.PricingStorage) *CostCalculator {
 	return &CostCalculator{
 		pricingStorage: pricingStorage,
+		patterns:       make(map[string]*regexp.Regexp),
 	}
 }
 
+// SetCurrency sets the reporting currency. An empty value keeps the default
+// currency of the pricing data.
+func (c *CostCalculator) SetCurrency(currency string) {
+	c.currency = strings.ToUpper(currency)
+}
+
+func (c *CostCalculator) reportCurrency() string {
+	if c.currency != "" {
+		return c.currency
+	}
+	return c.pricingStorage.GetDefaultCurrency()
+}
+
+// cspName returns the CSP the calculator prices against
+func (c *CostCalculator) cspName() string {
+	if c.csp != "" {
+		return c.csp
+	}
+	return c.pricingStorage.DefaultCSPName()
+}
+
+// forCSP returns a copy of the calculator that prices and discounts
+// against the given CSP; an empty name means default_csp
+func (c *CostCalculator) forCSP(name string) *CostCalculator {
+	scoped := *c
+	scoped.csp = name
+	return &scoped
+}
+
 func (c *CostCalculator) CalculateTotalCost(instances map[string]*storage.InstanceState, startTime, endTime time.Time) (*CostSummary, error) {
 	summary := &CostSummary{
 		Period: TimePeriod{
 			StartTime: startTime,
 			EndTime:   endTime,
 		},
-		TotalInstances: len(instances),
-		Currency:       "KRW",
+		Currency:       c.reportCurrency(),
 		InstanceCosts:  make([]InstanceCost, 0, len(instances)),
 	}
+	summary.TotalBaseCost = money.Zero(summary.Currency)
+	summary.TotalDiscount = money.Zero(summary.Currency)
+	summary.TotalFinalCost = money.Zero(summary.Currency)
+	summary.TotalSummaryDiscount = money.Zero(summary.Currency)
+
+	usedRates := make(map[string]storage.ExchangeRate)
+	calculated := make([]*storage.InstanceState, 0, len(instances))
+	estimated := make(map[string]int)
+	flavorNames := make(map[string]string)
 
 	for _, instance := range instances {
-		cost, err := c.calculateInstanceCost(instance, startTime, endTime)
+		// 기간 시작 전에 삭제된 인스턴스는 비용이 없으므로 제외
+		if instance.IsDeletedBefore(startTime) {
+			continue
+		}
+
+		calc := c.forCSP(instance.CSP)
+		cost, rate, err := calc.calculateInstanceCost(instance, startTime, endTime, summary.Currency)
+		var unpriced *UnpricedFlavorError
+		if errors.As(err, &unpriced) && !c.strict {
+			summary.Unpriced = append(summary.Unpriced, UnpricedInstance{
+				InstanceID:   instance.ID,
+				InstanceName: instance.Name,
+				CSP:          calc.cspName(),
+				FlavorID:     unpriced.FlavorID,
+				FlavorName:   calc.flavorName(unpriced.FlavorID, instance.Region),
+				Region:       instance.Region,
+			})
+			continue
+		}
 		if err != nil {
 			return nil, fmt.Errorf("인스턴스 %s 비용 계산 실패: %w", instance.ID, err)
 		}
+		if cost.EstimatedPrice {
+			estimated[cost.FlavorID]++
+			flavorNames[cost.FlavorID] = cost.FlavorName
+		}
+		if rate != nil {
+			usedRates[rate.From+"_to_"+rate.To] = *rate
+		}
 
 		summary.InstanceCosts = append(summary.InstanceCosts, *cost)
-		summary.TotalBaseCost += cost.BaseCost
-		summary.TotalDiscount += cost.TotalDiscount
-		summary.TotalFinalCost += cost.FinalCost
+		calculated = append(calculated, instance)
+		summary.TotalBaseCost = summary.TotalBaseCost.Add(cost.BaseCost)
+		summary.TotalDiscount = summary.TotalDiscount.Add(cost.TotalDiscount)
+		summary.TotalFinalCost = summary.TotalFinalCost.Add(cost.FinalCost)
+	}
+
+	summary.TotalInstances = len(summary.InstanceCosts)
+	sort.Slice(summary.Unpriced, func(i, j int) bool {
+		return summary.Unpriced[i].InstanceName < summary.Unpriced[j].InstanceName
+	})
+	summary.Warnings = unpricedWarnings(summary.Unpriced, estimated, flavorNames)
+	for _, cost := range summary.InstanceCosts {
+		for _, flavorID := range cost.UnavailableFlavors {
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("인스턴스 %s의 flavor %s는 리전 %s에서 제공되지 않는 것으로 등록되어 있습니다",
+				cost.InstanceName, flavorLabel(flavorID, c.forCSP(cost.CSP).flavorName(flavorID, cost.Region)), cost.Region))
+		}
 	}
 
+	// 항목 금액이 이미 반올림되어 있으므로 합계는 항목 합과 정확히 일치
+	c.applySummaryDiscounts(summary, calculated)
+	summary.CSPSubtotals = c.cspSubtotals(summary)
+
+	for _, rate := range usedRates {
+		summary.ExchangeRates = append(summary.ExchangeRates, rate)
+	}
+	sort.Slice(summary.ExchangeRates, func(i, j int) bool {
+		return summary.ExchangeRates[i].From < summary.ExchangeRates[j].From
+	})
+
 	return summary, nil
 }
 
@@ -93,219 +249,373 @@ func (c *CostCalculator) CalculateMonthlyEstimate(instances map[string]*storage.
 	return c.CalculateTotalCost(instances, startOfMonth, endOfMonth)
 }
 
-func (c *CostCalculator) calculateInstanceCost(instance *storage.InstanceState, startTime, endTime time.Time) (*InstanceCost, error) {
-	flavorPrice, exists := c.pricingStorage.GetFlavorPrice(instance.FlavorID)
-	if !exists {
-		return nil, fmt.Errorf("flavor %s에 대한 가격 정보를 찾을 수 없습니다", instance.FlavorID)
+func (c *CostCalculator) calculateInstanceCost(instance *storage.InstanceState, startTime, endTime time.Time, currency string) (*InstanceCost, *storage.ExchangeRate, error) {
+	segments := c.priceSegments(instance, startTime, endTime)
+	split := len(segments) > 1
+
+	var (
+		lineItems    []CostLineItem
+		runningHours float64
+		hourlyRate   money.Money
+		billingModel string
+		priceSource  string
+		estimated    bool
+		unavailable  []string
+		rate         *storage.ExchangeRate
+	)
+
+	// 리사이즈나 가격 변경이 있으면 구간마다 당시 flavor와 가격으로 계산
+	for _, segment := range segments {
+		flavorPrice, fallback, err := c.flavorPriceAt(segment.FlavorID, instance.Region, segment.Start)
+		if err != nil {
+			return nil, nil, err
+		}
+		estimated = estimated || fallback
+		if !c.pricingStorage.IsFlavorAvailable(c.cspName(), segment.FlavorID, instance.Region) {
+			unavailable = append(unavailable, segment.FlavorID)
+		}
+
+		price, source, segmentRate, err := c.convertPrice(flavorPrice, instance.Region, currency, segment.Start)
+		if err != nil {
+			return nil, nil, err
+		}
+		if priceSource != "converted" {
+			priceSource = source
+		}
+		if segmentRate != nil {
+			rate = segmentRate
+		}
+		hourlyRate = money.FromFloat(price.HourlyPrice, currency)
+
+		hours := c.calculateRunningHours(instance, segment.Start, segment.End)
+		runningCost, model := c.calculateBaseCost(instance, price, hours, segment.Start, segment.End, segment.Since)
+		runningHours += hours
+		billingModel = model
+
+		var items []CostLineItem
+		// 나뉜 구간 중 실행 시간이 없는 구간은 실행 항목을 생략
+		if runningAmount := c.roundAmount(runningCost); !split || hours > 0 || !runningAmount.IsZero() {
+			items = append(items, CostLineItem{
+				Type:       LineItemRunning,
+				Hours:      hours,
+				HourlyRate: hourlyRate,
+				Amount:     runningAmount,
+			})
+		}
+		// 약정 요금은 정지 여부와 관계없이 정액이므로 정지 시간을 따로 과금하지 않음
+		if model != storage.ContractMonthly && model != storage.ContractYearly {
+			items = append(items, c.stoppedLineItems(instance, price, segment.Start, segment.End)...)
+			items = append(items, c.storageLineItems(instance, price, segment.Start, segment.End)...)
+		}
+		if split {
+			for i := range items {
+				items[i].FlavorID = segment.FlavorID
+				items[i].FlavorName = c.flavorName(segment.FlavorID, instance.Region)
+				items[i].Start = segment.Start
+				items[i].End = segment.End
+			}
+		}
+		lineItems = append(lineItems, items...)
 	}
 
-	runningHours := c.calculateRunningHours(instance, startTime, endTime)
-	baseCost := runningHours * flavorPrice.HourlyPrice
+	baseCost := money.Zero(currency)
+	stoppedHours := 0.0
+	for _, item := range lineItems {
+		baseCost = baseCost.Add(item.Amount)
+		if item.Type != LineItemRunning {
+			stoppedHours += item.Hours
+		}
+	}
 
 	cost := &InstanceCost{
-		InstanceID:        instance.ID,
-		InstanceName:      instance.Name,
-		FlavorID:          instance.FlavorID,
-		FlavorName:        c.pricingStorage.GetFlavorName(instance.FlavorID),
-		BaseHourlyRate:    flavorPrice.HourlyPrice,
-		TotalRunningHours: runningHours,
-		BaseCost:          baseCost,
-		AppliedDiscounts:  []DiscountDetail{},
+		InstanceID:         instance.ID,
+		InstanceName:       instance.Name,
+		CSP:                c.cspName(),
+		DeletedAt:          instance.DeletedAt,
+		FlavorID:           instance.FlavorID,
+		FlavorName:         c.flavorName(instance.FlavorID, instance.Region),
+		Currency:           currency,
+		PriceSource:        priceSource,
+		EstimatedPrice:     estimated,
+		Region:             instance.Region,
+		UnavailableFlavors: slices.Compact(unavailable),
+		BaseHourlyRate:     hourlyRate,
+		TotalRunningHours:  runningHours,
+		TotalStoppedHours:  stoppedHours,
+		LineItems:          lineItems,
+		BaseCost:           baseCost,
+		BillingModel:       billingModel,
+		AppliedDiscounts:   []DiscountDetail{},
+	}
+	if rate != nil {
+		cost.ExchangeRate = rate.Rate
 	}
 
-	c.applyDiscounts(cost, instance)
+	c.applyDiscounts(cost, instance, startTime, endTime)
 
-	cost.FinalCost = cost.BaseCost - cost.TotalDiscount
+	// 할인 항목별로 반올림한 뒤 합계를 다시 구해 항목 합과 총액이 일치하도록 함
+	cost.TotalDiscount = money.Zero(currency)
+	for i := range cost.AppliedDiscounts {
+		cost.AppliedDiscounts[i].DiscountAmount = c.roundAmount(cost.AppliedDiscounts[i].DiscountAmount)
+		cost.TotalDiscount = cost.TotalDiscount.Add(cost.AppliedDiscounts[i].DiscountAmount)
+	}
 
-	return cost, nil
-}
+	cost.FinalCost = cost.BaseCost.Sub(cost.TotalDiscount)
 
-func (c *CostCalculator) calculateRunningHours(instance *storage.InstanceState, startTime, endTime time.Time) float64 {
-	totalHours := 0.0
-	
-	// StateHistory 우선 사용
-	if len(instance.StateHistory) > 0 {
-		totalHours += c.calculateHoursFromStateHistory(instance, startTime, endTime, true)
-	} else if len(instance.StatusHistory) > 0 {
-		totalHours += c.calculateHoursFromStatusHistory(instance, startTime, endTime, true)
-	} else {
-		// 기록이 없으면 현재 상태 기반 계산
-		totalHours += c.calculateHoursFromCurrentState(instance, startTime, endTime, true)
-	}
+	return cost, rate, nil
+}
 
-	return totalHours
+// flavorSegment is the part of the calculation period during which the
+// instance used one flavor at one price. Since is when that flavor came into
+// use.
+type flavorSegment struct {
+	FlavorID string
+	Start    time.Time
+	End      time.Time
+	Since    time.Time
 }
 
-func (c *CostCalculator) calculateHoursFromStateHistory(instance *storage.InstanceState, startTime, endTime time.Time, forRunning bool) float64 {
-	totalHours := 0.0
-	
-	// 생성시간이 계산 기간 내에 있고 현재 상태가 RUNNING이면 생성~첫 기록까지도 RUNNING으로 간주
-	if len(instance.StateHistory) > 0 {
-		firstRecord := &instance.StateHistory[0]
-		isCurrentlyRunning := instance.CurrentStatus == "ACTIVE" && instance.CurrentPowerState == 1
-		
-		// 생성시간부터 첫 기록까지의 시간 계산
-		if firstRecord.Timestamp.After(instance.CreatedAt) && firstRecord.Timestamp.After(startTime) {
-			preStart := instance.CreatedAt
-			if preStart.Before(startTime) {
-				preStart = startTime
-			}
-			
-			preEnd := firstRecord.Timestamp
-			if preEnd.After(endTime) {
-				preEnd = endTime
-			}
-			
-			if preEnd.After(preStart) && ((forRunning && isCurrentlyRunning) || (!forRunning && !isCurrentlyRunning)) {
-				duration := preEnd.Sub(preStart)
-				totalHours += duration.Hours()
+// priceSegments splits the period by the flavors the instance used and by
+// the catalog price changes of each flavor.
+func (c *CostCalculator) priceSegments(instance *storage.InstanceState, startTime, endTime time.Time) []flavorSegment {
+	var segments []flavorSegment
+	for _, segment := range flavorSegments(instance, startTime, endTime) {
+		for _, change := range c.pricingStorage.GetPriceChanges(c.cspName(), segment.FlavorID) {
+			if !change.After(segment.Start) || !change.Before(segment.End) {
+				continue
 			}
+			before := segment
+			before.End = change
+			segments = append(segments, before)
+			segment.Start = change
 		}
+		segments = append(segments, segment)
 	}
-	
-	// StateHistory 기록 계산
-	for i := 0; i < len(instance.StateHistory); i++ {
-		current := &instance.StateHistory[i]
-		isRecordRunning := current.Status == "ACTIVE" && current.PowerState == 1
-
-		if (forRunning && isRecordRunning) || (!forRunning && !isRecordRunning) {
-			periodStart := current.Timestamp
-			if periodStart.Before(startTime) {
-				periodStart = startTime
-			}
-
-			var periodEnd time.Time
-			if i+1 < len(instance.StateHistory) {
-				periodEnd = instance.StateHistory[i+1].Timestamp
-			} else {
-				periodEnd = instance.LastUpdated
-			}
-
-			if periodEnd.After(endTime) {
-				periodEnd = endTime
-			}
+	return segments
+}
 
-			if periodEnd.After(periodStart) {
-				duration := periodEnd.Sub(periodStart)
-				totalHours += duration.Hours()
-			}
+// flavorSegments splits the period by the flavors the instance used. The
+// current flavor covers the whole period when no flavor period overlaps it.
+func flavorSegments(instance *storage.InstanceState, startTime, endTime time.Time) []flavorSegment {
+	var segments []flavorSegment
+	for _, period := range instance.FlavorPeriods() {
+		start, end := period.Start, period.End
+		if start.Before(startTime) {
+			start = startTime
+		}
+		if end.IsZero() || end.After(endTime) {
+			end = endTime
+		}
+		if !end.After(start) {
+			continue
 		}
+		segments = append(segments, flavorSegment{FlavorID: period.FlavorID, Start: start, End: end, Since: period.Start})
 	}
 
-	return totalHours
+	if len(segments) == 0 {
+		segments = append(segments, flavorSegment{FlavorID: instance.FlavorID, Start: startTime, End: endTime, Since: instance.CreatedAt})
+	}
+	return segments
 }
 
-func (c *CostCalculator) calculateHoursFromStatusHistory(instance *storage.InstanceState, startTime, endTime time.Time, forRunning bool) float64 {
-	totalHours := 0.0
-
-	for i := 0; i < len(instance.StatusHistory); i++ {
-		current := &instance.StatusHistory[i]
-		isRecordRunning := current.Status == "ACTIVE" && current.PowerState == 1
+// convertPrice returns the flavor price in the report currency. A native
+// price listed for that currency at the given time in the region wins over
+// converting the default price.
+func (c *CostCalculator) convertPrice(flavorPrice *storage.FlavorPrice, region, currency string, at time.Time) (*storage.FlavorPrice, string, *storage.ExchangeRate, error) {
+	priceCurrency := flavorPrice.Currency
+	if priceCurrency == "" {
+		priceCurrency = c.pricingStorage.GetCSPCurrency(c.cspName())
+	}
 
-		if (forRunning && isRecordRunning) || (!forRunning && !isRecordRunning) {
-			periodStart := current.Timestamp
-			if periodStart.Before(startTime) {
-				periodStart = startTime
-			}
+	if priceCurrency == currency {
+		// 통화가 없는 기존 형식 가격도 금액 계산에 통화가 필요하므로 복사본에 통화를 지정
+		native := *flavorPrice
+		native.Currency = priceCurrency
+		return &native, "native", nil, nil
+	}
 
-			var periodEnd time.Time
-			if i+1 < len(instance.StatusHistory) {
-				periodEnd = instance.StatusHistory[i+1].Timestamp
-			} else {
-				periodEnd = instance.LastUpdated
-			}
+	if native, exists := c.pricingStorage.GetFlavorPriceInCurrencyAt(c.cspName(), region, flavorPrice.FlavorID, currency, at); exists {
+		return native, "native", nil, nil
+	}
 
-			if periodEnd.After(endTime) {
-				periodEnd = endTime
-			}
+	rate, err := c.pricingStorage.GetExchangeRate(priceCurrency, currency)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("flavor %s 가격 환산 실패: %w", flavorPrice.FlavorID, err)
+	}
 
-			if periodEnd.After(periodStart) {
-				duration := periodEnd.Sub(periodStart)
-				totalHours += duration.Hours()
-			}
-		}
+	converted := &storage.FlavorPrice{
+		FlavorID:     flavorPrice.FlavorID,
+		HourlyPrice:  flavorPrice.HourlyPrice * rate.Rate,
+		MonthlyPrice: flavorPrice.MonthlyPrice * rate.Rate,
+		YearlyPrice:  flavorPrice.YearlyPrice * rate.Rate,
+		Currency:     currency,
+	}
+	if flavorPrice.ShutdownHourlyPrice != nil {
+		shutdownHourly := *flavorPrice.ShutdownHourlyPrice * rate.Rate
+		converted.ShutdownHourlyPrice = &shutdownHourly
+	}
+	if flavorPrice.StorageHourlyPrice != nil {
+		storageHourly := *flavorPrice.StorageHourlyPrice * rate.Rate
+		converted.StorageHourlyPrice = &storageHourly
 	}
 
-	return totalHours
+	return converted, "converted", rate, nil
 }
 
-func (c *CostCalculator) calculateHoursFromCurrentState(instance *storage.InstanceState, startTime, endTime time.Time, forRunning bool) float64 {
-	isCurrentlyRunning := instance.CurrentStatus == "ACTIVE" && instance.CurrentPowerState == 1
-	
-	if (forRunning && !isCurrentlyRunning) || (!forRunning && isCurrentlyRunning) {
-		return 0
-	}
-	
-	// 인스턴스가 계산 기간과 겹치는 시간 계산
-	instanceStart := instance.CreatedAt
-	if instanceStart.Before(startTime) {
-		instanceStart = startTime
-	}
-	
-	instanceEnd := instance.LastUpdated
-	if instanceEnd.After(endTime) {
-		instanceEnd = endTime
+func (c *CostCalculator) calculateRunningHours(instance *storage.InstanceState, startTime, endTime time.Time) float64 {
+	return c.billableHours(c.stateSegments(instance, startTime, endTime, storage.BillingClassFull))
+}
+
+// timeSegment is the part of a continuous run in one billing class that
+// falls in the calculation period. RunStart and RunEnd bound the whole run,
+// which may extend past the period.
+type timeSegment struct {
+	Start    time.Time
+	End      time.Time
+	RunStart time.Time
+	RunEnd   time.Time
+}
+
+// stateSegments returns the segments of an instance within the period whose
+// status falls in the given billing class. Adjacent records are merged into
+// one run before clipping.
+func (c *CostCalculator) stateSegments(instance *storage.InstanceState, startTime, endTime time.Time, billingClass string) []timeSegment {
+	var runs []timeSegment
+	for _, period := range instance.StatusPeriods() {
+		if c.billingClass(period.Status, period.PowerState) != billingClass || !period.End.After(period.Start) {
+			continue
+		}
+		if n := len(runs); n > 0 && runs[n-1].RunEnd.Equal(period.Start) {
+			runs[n-1].RunEnd = period.End
+			continue
+		}
+		runs = append(runs, timeSegment{RunStart: period.Start, RunEnd: period.End})
 	}
-	
-	if instanceEnd.After(instanceStart) {
-		duration := instanceEnd.Sub(instanceStart)
-		return duration.Hours()
+
+	var segments []timeSegment
+	for _, run := range runs {
+		run.Start, run.End = run.RunStart, run.RunEnd
+		if run.Start.Before(startTime) {
+			run.Start = startTime
+		}
+		if run.End.After(endTime) {
+			run.End = endTime
+		}
+		if run.End.After(run.Start) {
+			segments = append(segments, run)
+		}
 	}
-	
-	return 0
+	return segments
 }
 
-func (c *CostCalculator) applyDiscounts(cost *InstanceCost, instance *storage.InstanceState) {
-	// Apply legacy hardcoded discount if new format is not available
+func (c *CostCalculator) applyDiscounts(cost *InstanceCost, instance *storage.InstanceState, startTime, endTime time.Time) {
+	// Legacy format has no discount rules; NHN's stopped-instance rate is
+	// billed as a stopped line item instead of a discount
 	if !c.pricingStorage.IsNewFormat() {
-		if c.isEligibleForShutdownDiscount(instance) {
-			discountPercent := 90.0
-			discountAmount := cost.BaseCost * (discountPercent / 100.0)
-			
-			cost.AppliedDiscounts = append(cost.AppliedDiscounts, DiscountDetail{
-				RuleName:        "NHN 인스턴스 셧다운 90일 할인",
-				DiscountPercent: discountPercent,
-				DiscountAmount:  discountAmount,
-			})
-			
-			cost.TotalDiscount += discountAmount
-		}
 		return
 	}
 	
 	// Apply discounts from new pricing schema
-	defaultCSP := c.pricingStorage.DefaultCSP
-	if defaultCSP == "" {
-		defaultCSP = "nhn"
-	}
+	cspName := c.cspName()
 	
-	// Apply global discount rules first
-	for _, rule := range c.pricingStorage.GlobalDiscountRules {
-		if rule.Enabled && c.evaluateDiscountRule(&rule, cost, instance) {
-			discountAmount := cost.BaseCost * (rule.DiscountPercent / 100.0)
-			cost.AppliedDiscounts = append(cost.AppliedDiscounts, DiscountDetail{
-				RuleName:        rule.Name,
-				DiscountPercent: rule.DiscountPercent,
-				DiscountAmount:  discountAmount,
-			})
-			cost.TotalDiscount += discountAmount
-		}
+	// Global rules come before CSP-specific rules when priorities are equal
+	rules := make([]storage.DiscountRule, 0, len(c.pricingStorage.GlobalDiscountRules))
+	rules = append(rules, c.pricingStorage.GlobalDiscountRules...)
+	if csp, exists := c.pricingStorage.CSPs[cspName]; exists {
+		rules = append(rules, csp.DiscountRules...)
 	}
-	
-	// Apply CSP-specific discount rules
-	if csp, exists := c.pricingStorage.CSPs[defaultCSP]; exists {
-		for _, rule := range csp.DiscountRules {
-			if rule.Enabled && c.evaluateDiscountRule(&rule, cost, instance) {
-				discountAmount := cost.BaseCost * (rule.DiscountPercent / 100.0)
-				cost.AppliedDiscounts = append(cost.AppliedDiscounts, DiscountDetail{
-					RuleName:        rule.Name,
-					DiscountPercent: rule.DiscountPercent,
-					DiscountAmount:  discountAmount,
-				})
-				cost.TotalDiscount += discountAmount
+
+	c.resolveDiscounts(rules, cost, instance, startTime, endTime)
+}
+
+// effectiveRatio returns the portion of the instance's base cost incurred
+// while the rule was effective, together with the overlapping window. Each
+// line item is pro-rated by its own hours inside the window, so stopped and
+// storage hours count as well as running hours. Rules with invalid dates or
+// no overlap are skipped.
+func (c *CostCalculator) effectiveRatio(rule *storage.DiscountRule, instance *storage.InstanceState, cost *InstanceCost, startTime, endTime time.Time) (float64, TimePeriod, bool) {
+	window := TimePeriod{StartTime: startTime, EndTime: endTime}
+
+	from, to, err := rule.EffectiveWindow()
+	if err != nil {
+		return 0, window, false
+	}
+
+	if !from.IsZero() && from.After(window.StartTime) {
+		window.StartTime = from
+	}
+	if !to.IsZero() && to.Before(window.EndTime) {
+		window.EndTime = to
+	}
+	if !window.EndTime.After(window.StartTime) {
+		return 0, window, false
+	}
+
+	if window.StartTime.Equal(startTime) && window.EndTime.Equal(endTime) {
+		return 1, window, true
+	}
+
+	if !cost.BaseCost.IsPositive() {
+		// 비용이 없으면 기간 비율로 안분
+		return window.EndTime.Sub(window.StartTime).Hours() / endTime.Sub(startTime).Hours(), window, true
+	}
+
+	inWindow := money.Zero(cost.Currency)
+	for _, item := range cost.LineItems {
+		inWindow = inWindow.Add(item.Amount.Mul(c.lineItemShare(item, instance, cost.BillingModel, window, startTime, endTime)))
+	}
+	return inWindow.Ratio(cost.BaseCost), window, true
+}
+
+// lineItemShare returns the part of a line item's hours that falls in the
+// window. Contract charges are flat, so they are pro-rated by time.
+func (c *CostCalculator) lineItemShare(item CostLineItem, instance *storage.InstanceState, billingModel string, window TimePeriod, startTime, endTime time.Time) float64 {
+	from, to := startTime, endTime
+	if !item.Start.IsZero() {
+		from, to = item.Start, item.End
+	}
+
+	// 정지 항목은 감면 기간 경계에서 나뉨 (stoppedLineItems)
+	if billing, exists := c.pricingStorage.GetShutdownBilling(c.cspName()); exists && billing.EligibleDays > 0 {
+		eligibleEnd := instance.CreatedAt.AddDate(0, 0, billing.EligibleDays)
+		switch item.Type {
+		case LineItemStopped:
+			if eligibleEnd.Before(to) {
+				to = eligibleEnd
+			}
+		case LineItemStoppedAfterWindow:
+			if eligibleEnd.After(from) {
+				from = eligibleEnd
 			}
 		}
 	}
+
+	itemDuration := to.Sub(from)
+	if window.StartTime.After(from) {
+		from = window.StartTime
+	}
+	if window.EndTime.Before(to) {
+		to = window.EndTime
+	}
+	if !to.After(from) || itemDuration <= 0 {
+		return 0
+	}
+
+	var hours float64
+	switch {
+	case item.Type == LineItemRunning && (billingModel == storage.ContractMonthly || billingModel == storage.ContractYearly):
+		return to.Sub(from).Hours() / itemDuration.Hours()
+	case item.Type == LineItemRunning:
+		hours = c.calculateRunningHours(instance, from, to)
+	case item.Type == LineItemStorage:
+		hours = c.meteredHours(c.stateSegments(instance, from, to, storage.BillingClassStorageOnly), 0)
+	default:
+		hours = c.meteredHours(c.stateSegments(instance, from, to, storage.BillingClassReduced), 0)
+	}
+	if item.Hours <= 0 {
+		return 0
+	}
+	return min(hours/item.Hours, 1)
 }
 
 func (c *CostCalculator) evaluateDiscountRule(rule *storage.DiscountRule, cost *InstanceCost, instance *storage.InstanceState) bool {
@@ -318,6 +628,10 @@ func (c *CostCalculator) evaluateDiscountRule(rule *storage.DiscountRule, cost *
 }
 
 func (c *CostCalculator) evaluateCondition(condition *storage.DiscountCondition, cost *InstanceCost, instance *storage.InstanceState) bool {
+	if condition.IsGroup() {
+		return c.evaluateGroup(condition, cost, instance)
+	}
+
 	now := time.Now()
 	
 	switch condition.Type {
@@ -340,17 +654,60 @@ func (c *CostCalculator) evaluateCondition(condition *storage.DiscountCondition,
 		return c.compareValues(monthlyHours, condition.Operator, condition.Value)
 		
 	case "instance_status":
-		expectedStatus := fmt.Sprintf("%v", condition.Value)
-		currentStatus := "RUNNING"
-		if instance.CurrentStatus == "SHUTOFF" || instance.CurrentPowerState == 4 {
-			currentStatus = "SHUTDOWN"
-		}
-		return currentStatus == expectedStatus
+		currentStatus := storage.EffectiveStatus(instance.CurrentStatus, instance.CurrentPowerState)
+		return c.compareStrings(currentStatus, condition.Operator, statusConditionValue(condition.Value))
 		
 	case "shutdown_age_days":
 		// NHN Cloud specific: 90일 이내 생성된 인스턴스가 SHUTDOWN 상태일 때만 할인
 		ageInDays := int(now.Sub(instance.CreatedAt).Hours() / 24)
 		return c.compareValues(float64(ageInDays), condition.Operator, condition.Value)
+
+	case "vcpu", "memory_mb":
+		instanceType, exists := c.pricingStorage.GetInstanceTypeInfo(c.cspName(), instance.FlavorID)
+		if !exists {
+			return false
+		}
+		actual := instanceType.VCPU
+		if condition.Type == "memory_mb" {
+			actual = instanceType.MemoryMB
+		}
+		return c.compareValues(float64(actual), condition.Operator, condition.Value)
+
+	case "flavor_id":
+		return c.compareStrings(instance.FlavorID, condition.Operator, condition.Value)
+
+	case "flavor_name":
+		return c.compareStrings(c.flavorName(instance.FlavorID, instance.Region), condition.Operator, condition.Value)
+
+	case "region":
+		return c.compareStrings(instance.Region, condition.Operator, condition.Value)
+
+	case "csp":
+		return c.compareStrings(c.cspName(), condition.Operator, condition.Value)
+
+	case "instance_name":
+		return c.compareStrings(instance.Name, condition.Operator, condition.Value)
+
+	case "tag":
+		// 태그 중 하나라도 조건을 만족하면 참 (부정 연산자는 모든 태그가 만족해야 참)
+		negated := condition.Operator == "!=" || condition.Operator == "not_in"
+		for _, tag := range instance.Tags {
+			matched := c.compareStrings(tag, condition.Operator, condition.Value)
+			if matched && !negated {
+				return true
+			}
+			if !matched && negated {
+				return false
+			}
+		}
+		return negated
+
+	case "metadata":
+		value, exists := instance.Metadata[condition.Key]
+		if !exists {
+			return false
+		}
+		return c.compareStrings(value, condition.Operator, condition.Value)
 		
 	default:
 		return false
@@ -358,6 +715,17 @@ func (c *CostCalculator) evaluateCondition(condition *storage.DiscountCondition,
 }
 
 func (c *CostCalculator) compareValues(actual float64, operator string, expectedValue any) bool {
+	if operator == "in" || operator == "not_in" {
+		found := false
+		for _, item := range conditionList(expectedValue) {
+			if c.compareValues(actual, "==", item) {
+				found = true
+				break
+			}
+		}
+		return found == (operator == "in")
+	}
+
 	var expected float64
 	
 	// Type assertion for expected value
@@ -386,23 +754,9 @@ func (c *CostCalculator) compareValues(actual float64, operator string, expected
 		return actual > expected
 	case "==":
 		return actual == expected
+	case "!=":
+		return actual != expected
 	default:
 		return false
 	}
 }
-
-// Legacy discount check for backward compatibility
-func (c *CostCalculator) isEligibleForShutdownDiscount(instance *storage.InstanceState) bool {
-	// 1. SHUTDOWN 상태 확인
-	isShutdown := instance.CurrentStatus == "SHUTOFF" || instance.CurrentPowerState == 4
-	if !isShutdown {
-		return false
-	}
-	
-	// 2. NHN Cloud 정작: 생성된지 90일 이내인지 확인 (이전 코드가 잘못되었음)
-	now := time.Now()
-	ageInDays := now.Sub(instance.CreatedAt).Hours() / 24
-	isWithin90Days := ageInDays <= 90
-	
-	return isWithin90Days
-}
\ No newline at end of file
@@ -0,0 +1,128 @@
+package calculator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"costcli/pkg/storage"
+)
+
+// evaluateGroup evaluates all/any/not groups. Every key present on the
+// condition must hold, so {"all": [...], "not": {...}} means both.
+func (c *CostCalculator) evaluateGroup(condition *storage.DiscountCondition, cost *InstanceCost, instance *storage.InstanceState) bool {
+	if condition.All != nil {
+		for i := range condition.All {
+			if !c.evaluateCondition(&condition.All[i], cost, instance) {
+				return false
+			}
+		}
+	}
+
+	if condition.Any != nil {
+		matched := false
+		for i := range condition.Any {
+			if c.evaluateCondition(&condition.Any[i], cost, instance) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if condition.Not != nil && c.evaluateCondition(condition.Not, cost, instance) {
+		return false
+	}
+
+	return true
+}
+
+// compareStrings compares a string attribute with ==, !=, in, not_in or
+// matches (regular expression).
+func (c *CostCalculator) compareStrings(actual, operator string, expectedValue any) bool {
+	switch operator {
+	case "==":
+		return actual == fmt.Sprintf("%v", expectedValue)
+	case "!=":
+		return actual != fmt.Sprintf("%v", expectedValue)
+	case "in", "not_in":
+		found := false
+		for _, item := range conditionList(expectedValue) {
+			if actual == fmt.Sprintf("%v", item) {
+				found = true
+				break
+			}
+		}
+		return found == (operator == "in")
+	case "matches":
+		pattern, ok := expectedValue.(string)
+		if !ok {
+			return false
+		}
+		re := c.compilePattern(pattern)
+		return re != nil && re.MatchString(actual)
+	default:
+		return false
+	}
+}
+
+// compilePattern returns the compiled regular expression of a matches
+// condition, compiling each pattern once. Invalid patterns return nil.
+func (c *CostCalculator) compilePattern(pattern string) *regexp.Regexp {
+	if re, exists := c.patterns[pattern]; exists {
+		return re
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = nil
+	}
+	c.patterns[pattern] = re
+	return re
+}
+
+// conditionList normalizes a condition value into a list for in/not_in
+func conditionList(value any) []any {
+	switch v := value.(type) {
+	case []any:
+		return v
+	case []string:
+		list := make([]any, 0, len(v))
+		for _, item := range v {
+			list = append(list, item)
+		}
+		return list
+	case nil:
+		return nil
+	default:
+		return []any{v}
+	}
+}
+
+// legacyStatusNames maps the old binary status names used in conditions to
+// OpenStack statuses
+var legacyStatusNames = map[string]string{
+	"RUNNING":  storage.StatusActive,
+	"SHUTDOWN": storage.StatusShutoff,
+}
+
+// statusConditionValue rewrites RUNNING/SHUTDOWN in an instance_status
+// condition value to ACTIVE/SHUTOFF
+func statusConditionValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		if status, exists := legacyStatusNames[strings.ToUpper(v)]; exists {
+			return status
+		}
+		return strings.ToUpper(v)
+	case []any:
+		converted := make([]any, len(v))
+		for i, item := range v {
+			converted[i] = statusConditionValue(item)
+		}
+		return converted
+	default:
+		return value
+	}
+}
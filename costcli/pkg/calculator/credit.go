@@ -0,0 +1,220 @@
+package calculator
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"costcli/pkg/money"
+	"costcli/pkg/storage"
+)
+
+// burnWindowDays is how many recent days are used to project exhaustion
+const burnWindowDays = 7
+
+// CreditBalance is the consumption state of a single credit
+type CreditBalance struct {
+	Credit              storage.Credit `json:"credit"`
+	Used                money.Money    `json:"used"`
+	Remaining           money.Money    `json:"remaining"`
+	Expired             bool           `json:"expired"`
+	DailyBurn           money.Money    `json:"daily_burn"`
+	ProjectedExhaustion *time.Time     `json:"projected_exhaustion,omitempty"`
+}
+
+// CalculateCreditBalances replays daily costs from the earliest credit up to
+// now and consumes credits chronologically. Days are split where a credit
+// starts or ends, and in each interval the valid, in-scope credit that
+// expires first is used first.
+func (c *CostCalculator) CalculateCreditBalances(instances map[string]*storage.InstanceState, credits []storage.Credit, now time.Time) ([]CreditBalance, error) {
+	balances := make([]CreditBalance, len(credits))
+	if len(credits) == 0 {
+		return balances, nil
+	}
+
+	start := credits[0].ValidFrom
+	for i, credit := range credits {
+		balances[i] = CreditBalance{
+			Credit:    credit,
+			Used:      money.Zero(credit.Currency),
+			Remaining: money.FromFloat(credit.Amount, credit.Currency),
+		}
+		if credit.ValidFrom.Before(start) {
+			start = credit.ValidFrom
+		}
+	}
+
+	order := make([]int, len(credits))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		toA, toB := credits[order[a]].ValidTo, credits[order[b]].ValidTo
+		if toA == nil || toB == nil {
+			return toA != nil
+		}
+		return toA.Before(*toB)
+	})
+
+	burnStart := now.AddDate(0, 0, -burnWindowDays)
+	recentUse := make([]money.Money, len(credits))
+	for i, credit := range credits {
+		recentUse[i] = money.Zero(credit.Currency)
+	}
+
+	// 크레딧 유효 기간과 소진 속도 계산 기간의 경계에서 구간을 나누어,
+	// 각 구간에서는 유효한 크레딧이 구간 비용 전체를 사용하도록 함
+	cuts := []time.Time{burnStart}
+	for _, credit := range credits {
+		cuts = append(cuts, credit.ValidFrom)
+		if credit.ValidTo != nil {
+			cuts = append(cuts, *credit.ValidTo)
+		}
+	}
+	periods, err := c.creditPeriods(instances, start, now, cuts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, period := range periods {
+		for _, cost := range period.Costs {
+			due := cost.Amount
+			instance := instances[cost.InstanceID]
+
+			for _, i := range order {
+				if !due.IsPositive() {
+					break
+				}
+				balance := &balances[i]
+				if !balance.Remaining.IsPositive() || !balance.Credit.IsValidAt(period.Start) || !balance.Credit.Scope.Matches(instance) {
+					continue
+				}
+
+				rate, err := c.pricingStorage.GetExchangeRate(period.Currency, balance.Credit.Currency)
+				if err != nil {
+					return nil, fmt.Errorf("크레딧 %s 환산 실패: %w", balance.Credit.ID, err)
+				}
+
+				consumed := money.Min(due.Convert(rate.Rate, balance.Credit.Currency), balance.Remaining)
+				balance.Remaining = balance.Remaining.Sub(consumed)
+				balance.Used = balance.Used.Add(consumed)
+				due = due.Sub(consumed.Convert(1/rate.Rate, period.Currency))
+
+				if !period.Start.Before(burnStart) {
+					recentUse[i] = recentUse[i].Add(consumed)
+				}
+			}
+		}
+	}
+
+	for i := range balances {
+		balance := &balances[i]
+		balance.Expired = balance.Credit.ValidTo != nil && !now.Before(*balance.Credit.ValidTo)
+		balance.DailyBurn = recentUse[i].Mul(1.0 / burnWindowDays)
+
+		if balance.Expired || !balance.Remaining.IsPositive() || !balance.DailyBurn.IsPositive() {
+			continue
+		}
+
+		days := balance.Remaining.Ratio(balance.DailyBurn)
+		exhaustion := now.Add(time.Duration(days * 24 * float64(time.Hour)))
+		if balance.Credit.ValidTo != nil && exhaustion.After(*balance.Credit.ValidTo) {
+			continue // 만료 전에 소진되지 않음
+		}
+		balance.ProjectedExhaustion = &exhaustion
+	}
+
+	return balances, nil
+}
+
+// creditPeriod is the cost of each instance in one interval of the replay
+type creditPeriod struct {
+	Start    time.Time
+	End      time.Time
+	Currency string
+	Total    money.Money
+	Costs    []instanceDue
+}
+
+// instanceDue is the cost of an instance in a creditPeriod, including its
+// share of the summary discounts
+type instanceDue struct {
+	InstanceID string
+	Amount     money.Money
+}
+
+// creditPeriods splits [start, now) at day boundaries and at the given cut
+// times. The cost of each interval is the cumulative cost since the start of
+// its billing month minus the cumulative cost up to the interval, so monthly
+// caps, minimum charges and tiers are charged once and the intervals of a
+// month add up to the month's total.
+func (c *CostCalculator) creditPeriods(instances map[string]*storage.InstanceState, start, now time.Time, cuts []time.Time) ([]creditPeriod, error) {
+	loc := now.Location()
+	var bounds []time.Time
+	for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc); day.Before(now); day = day.AddDate(0, 0, 1) {
+		bounds = append(bounds, day)
+	}
+	if len(bounds) == 0 {
+		return nil, nil
+	}
+	bounds = append(bounds, now)
+	for _, cut := range cuts {
+		if cut.After(bounds[0]) && cut.Before(now) {
+			bounds = append(bounds, cut)
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i].Before(bounds[j]) })
+	bounds = slices.CompactFunc(bounds, time.Time.Equal)
+
+	var (
+		periods    []creditPeriod
+		monthStart time.Time
+		prevTotal  money.Money
+		prev       map[string]money.Money
+	)
+	for i := 0; i+1 < len(bounds); i++ {
+		periodStart, periodEnd := bounds[i], bounds[i+1]
+		if month := time.Date(periodStart.Year(), periodStart.Month(), 1, 0, 0, 0, 0, loc); !month.Equal(monthStart) {
+			monthStart = month
+			prevTotal = money.Zero(c.reportCurrency())
+			prev = map[string]money.Money{}
+		}
+
+		summary, err := c.CalculateTotalCost(instances, monthStart, periodEnd)
+		if err != nil {
+			return nil, fmt.Errorf("%s 비용 계산 실패: %w", periodStart.Format("2006-01-02"), err)
+		}
+
+		// 요약 할인을 인스턴스별 비용에 비례 반영
+		instanceTotal := money.Zero(summary.Currency)
+		for _, cost := range summary.InstanceCosts {
+			instanceTotal = instanceTotal.Add(cost.FinalCost)
+		}
+		scale := 1.0
+		if instanceTotal.IsPositive() {
+			scale = summary.TotalFinalCost.Ratio(instanceTotal)
+		}
+
+		period := creditPeriod{
+			Start:    periodStart,
+			End:      periodEnd,
+			Currency: summary.Currency,
+			Total:    summary.TotalFinalCost.Sub(prevTotal),
+		}
+		cumulative := make(map[string]money.Money, len(summary.InstanceCosts))
+		for _, cost := range summary.InstanceCosts {
+			amount := cost.FinalCost.Mul(scale)
+			cumulative[cost.InstanceID] = amount
+			if before, ok := prev[cost.InstanceID]; ok {
+				amount = amount.Sub(before)
+			}
+			period.Costs = append(period.Costs, instanceDue{InstanceID: cost.InstanceID, Amount: amount})
+		}
+
+		periods = append(periods, period)
+		prevTotal = summary.TotalFinalCost
+		prev = cumulative
+	}
+	return periods, nil
+}
@@ -0,0 +1,131 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"costcli/pkg/money"
+	"costcli/pkg/storage"
+)
+
+const creditPricing = `{
+  "version": "2.0.0",
+  "default_csp": "nhn",
+  "csps": {
+    "nhn": {
+      "name": "nhn",
+      "default_currency": "KRW",
+      "billing_model": "monthly_cap",
+      "billing_rules": {"granularity": "hour", "minimum_billable_seconds": 3600},
+      "instance_types": {
+        "f1": {"id": "f1", "name": "m2.c2m4", "pricing": {"KRW": {"hourly": 100, "monthly": 30000}}}
+      },
+      "discount_rules": []
+    }
+  }
+}`
+
+func newTestCalculator(t *testing.T, pricing string) *CostCalculator {
+	t.Helper()
+	pricingStorage := storage.NewPricingStorage()
+	if err := pricingStorage.Load([]byte(pricing)); err != nil {
+		t.Fatalf("load pricing: %v", err)
+	}
+	return NewCostCalculator(pricingStorage)
+}
+
+// creditInstances returns an instance that runs all of August and reaches the
+// monthly cap mid-month, and one that runs for 20 minutes and is charged the
+// minimum hour
+func creditInstances() map[string]*storage.InstanceState {
+	created := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	short := time.Date(2026, 8, 10, 10, 20, 0, 0, time.UTC)
+	seen := time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)
+	return map[string]*storage.InstanceState{
+		"vm-1": {
+			ID: "vm-1", FlavorID: "f1", CurrentStatus: storage.StatusActive, CurrentPowerState: 1,
+			CreatedAt: created, LastUpdated: created, LastSeen: seen,
+		},
+		"vm-2": {
+			ID: "vm-2", FlavorID: "f1", CurrentStatus: storage.StatusShutoff, CurrentPowerState: 4,
+			CreatedAt: short, LastUpdated: short.Add(20 * time.Minute), LastSeen: seen,
+			StatusHistory: []storage.StatusHistoryItem{
+				{Status: storage.StatusActive, PowerState: 1, Timestamp: short},
+				{Status: storage.StatusShutoff, PowerState: 4, Timestamp: short.Add(20 * time.Minute)},
+			},
+		},
+	}
+}
+
+func TestCreditPeriodsAddUpToMonthlyTotal(t *testing.T) {
+	calc := newTestCalculator(t, creditPricing)
+	instances := creditInstances()
+	monthStart := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+
+	cut := time.Date(2026, 8, 15, 12, 0, 0, 0, time.UTC)
+	periods, err := calc.creditPeriods(instances, monthStart, monthEnd, []time.Time{cut})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(periods) != 32 {
+		t.Fatalf("got %d periods, want 31 days with one split at %s", len(periods), cut)
+	}
+
+	total := money.Zero("KRW")
+	for _, period := range periods {
+		instanceSum := money.Zero("KRW")
+		for _, cost := range period.Costs {
+			instanceSum = instanceSum.Add(cost.Amount)
+		}
+		if instanceSum.Sub(period.Total).Float64() > 1e-6 || period.Total.Sub(instanceSum).Float64() > 1e-6 {
+			t.Errorf("%s: instance costs %s, want period total %s", period.Start, instanceSum, period.Total)
+		}
+		total = total.Add(period.Total)
+	}
+
+	summary, err := calc.CalculateTotalCost(instances, monthStart, monthEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// vm-1은 월 요금 상한, vm-2는 최소 과금 1시간
+	if want := money.FromFloat(30100, "KRW"); summary.TotalFinalCost.Cmp(want) != 0 {
+		t.Fatalf("monthly total = %s, want %s", summary.TotalFinalCost, want)
+	}
+	if total.Cmp(summary.TotalFinalCost) != 0 {
+		t.Errorf("daily amounts add up to %s, want the monthly total %s", total, summary.TotalFinalCost)
+	}
+}
+
+func TestCreditConsumesOnlyWithinValidity(t *testing.T) {
+	calc := newTestCalculator(t, creditPricing)
+	instances := creditInstances()
+	monthStart := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 8, 20, 0, 0, 0, 0, time.UTC)
+
+	// 하루 중간에 시작하고 끝나는 크레딧은 유효한 시간의 비용만 사용
+	validFrom := time.Date(2026, 8, 3, 15, 0, 0, 0, time.UTC)
+	validTo := time.Date(2026, 8, 5, 9, 0, 0, 0, time.UTC)
+	credits := []storage.Credit{{ID: "c1", Amount: 1000000, Currency: "KRW", ValidFrom: validFrom, ValidTo: &validTo}}
+
+	balances, err := calc.CalculateCreditBalances(instances, credits, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := calc.CalculateTotalCost(instances, monthStart, validFrom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	through, err := calc.CalculateTotalCost(instances, monthStart, validTo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := through.TotalFinalCost.Sub(before.TotalFinalCost)
+	if want.Cmp(money.FromFloat(4200, "KRW")) != 0 {
+		t.Fatalf("cost within validity = %s, want 42 hours of vm-1", want)
+	}
+	if balances[0].Used.Cmp(want) != 0 {
+		t.Errorf("used = %s, want %s", balances[0].Used, want)
+	}
+}
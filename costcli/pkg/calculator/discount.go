@@ -0,0 +1,412 @@
+package calculator
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"costcli/pkg/money"
+	"costcli/pkg/storage"
+)
+
+// discountCandidate is a rule whose conditions and effective window matched
+type discountCandidate struct {
+	rule   *storage.DiscountRule
+	ratio  float64
+	window TimePeriod
+
+	// 요약 규칙에서 조건을 만족하는 인스턴스만의 비용과 시간 (nil이면 basis 전체)
+	matched *discountBasis
+}
+
+// resolveDiscounts combines every matching rule into AppliedDiscounts.
+//
+// Rules are applied in ascending priority (ties keep file order, global rules
+// first). Only the largest rule of each exclusive group is kept. Additive rules
+// discount the base cost, compound rules discount what is left after earlier
+// rules. Per-rule caps and the total discount cap are applied last, so the
+// final cost can never become negative.
+func (c *CostCalculator) resolveDiscounts(rules []storage.DiscountRule, cost *InstanceCost, instance *storage.InstanceState, startTime, endTime time.Time) {
+	candidates := make([]discountCandidate, 0, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		// 셧다운 규칙은 할인이 아니라 정지 시간 요금으로 반영됨 (stoppedLineItems)
+		if !rule.Enabled || rule.Scope == storage.DiscountScopeSummary || rule.Type == storage.DiscountTypeShutdown {
+			continue
+		}
+
+		ratio, window, ok := c.effectiveRatio(rule, instance, cost, startTime, endTime)
+		if !ok || !c.evaluateDiscountRule(rule, cost, instance) {
+			continue
+		}
+
+		candidates = append(candidates, discountCandidate{rule: rule, ratio: ratio, window: window})
+	}
+
+	basis := discountBasis{
+		baseCost: cost.BaseCost,
+		hours:    cost.TotalRunningHours,
+		currency: cost.Currency,
+		period:   TimePeriod{StartTime: startTime, EndTime: endTime},
+	}
+	cost.AppliedDiscounts, cost.SkippedDiscounts, cost.TotalDiscount = c.stackDiscounts(candidates, basis, cost.AppliedDiscounts, cost.SkippedDiscounts)
+}
+
+// discountBasis is what a set of rules is applied against: a single instance
+// or, for summary-scoped rules, the total of matching instances.
+type discountBasis struct {
+	baseCost money.Money
+	hours    float64
+	currency string
+	period   TimePeriod
+}
+
+// stackDiscounts orders, filters and caps candidates and returns the applied
+// and skipped details together with the total discount.
+func (c *CostCalculator) stackDiscounts(candidates []discountCandidate, basis discountBasis, applied, skipped []DiscountDetail) ([]DiscountDetail, []DiscountDetail, money.Money) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].rule.Priority < candidates[j].rule.Priority
+	})
+
+	candidates, skipped = c.dropUnconvertible(candidates, basis.currency, skipped)
+	candidates, skipped = c.pickExclusiveWinners(candidates, basis, skipped)
+
+	policy := c.discountPolicy()
+	maxTotal := basis.baseCost
+	if policy.MaxTotalDiscountPercent != nil {
+		maxTotal = basis.baseCost.Mul(*policy.MaxTotalDiscountPercent / 100.0)
+	}
+
+	total := money.Zero(basis.currency)
+	for _, candidate := range candidates {
+		rule := candidate.rule
+		mode := c.stackMode(rule)
+
+		resolution := "기본 비용 기준 합산"
+		if mode == storage.StackModeCompound {
+			resolution = "이전 할인 적용 후 금액 기준 복리"
+		}
+
+		amount := c.ruleAmount(candidate, basis, mode, basis.baseCost.Sub(total))
+
+		if limit, _ := c.ruleCap(rule, basis.currency); limit.IsPositive() && amount.Cmp(limit) > 0 {
+			amount = limit
+			resolution += fmt.Sprintf(", 규칙 상한 %s %s 적용", limit, basis.currency)
+		}
+
+		if rule.ExclusiveGroup != "" {
+			resolution += fmt.Sprintf(", 그룹 '%s'에서 최대 할인으로 선택", rule.ExclusiveGroup)
+		}
+
+		remaining := maxTotal.Sub(total)
+		if amount.Cmp(remaining) > 0 {
+			amount = remaining
+			resolution += ", 총 할인 상한 도달로 감액"
+		}
+
+		detail := c.discountDetail(candidate, mode)
+		if !amount.IsPositive() {
+			detail.Resolution = "총 할인 상한 도달로 제외"
+			skipped = append(skipped, detail)
+			continue
+		}
+
+		detail.DiscountAmount = amount
+		detail.Resolution = resolution
+		applied = append(applied, detail)
+		total = total.Add(amount)
+	}
+
+	return applied, skipped, total
+}
+
+// ruleAmount returns the uncapped discount of a rule. remaining is the cost
+// left after earlier rules and is used by compound rules.
+func (c *CostCalculator) ruleAmount(candidate discountCandidate, basis discountBasis, mode string, remaining money.Money) money.Money {
+	rule := candidate.rule
+
+	base, hours := basis.baseCost, basis.hours
+	if candidate.matched != nil {
+		base, hours = candidate.matched.baseCost, candidate.matched.hours
+	}
+
+	target := base
+	if mode == storage.StackModeCompound {
+		// 앞선 할인 후 남은 금액 중 이 규칙 대상의 몫
+		target = remaining.Mul(base.Ratio(basis.baseCost))
+	}
+
+	switch rule.Method {
+	case storage.DiscountMethodFixed:
+		ratio := candidate.ratio
+		if rule.AmountPer == storage.AmountPerMonth {
+			ratio *= monthFraction(basis.period.StartTime, basis.period.EndTime)
+		}
+		// 환율이 없는 규칙은 dropUnconvertible에서 제외됨
+		amount, _ := c.fromDefaultCurrency(rule.DiscountAmount, basis.currency)
+		return money.Min(amount.Mul(ratio), target)
+
+	case storage.DiscountMethodTiered:
+		if !base.IsPositive() {
+			return money.Zero(basis.currency)
+		}
+		amount := tieredAmount(rule.Tiers, hours, base)
+		return amount.Mul(target.Ratio(base) * candidate.ratio)
+
+	default:
+		return target.Mul(rule.DiscountPercent / 100.0 * candidate.ratio)
+	}
+}
+
+// tieredAmount applies each tier's percent to the hours that fall in it,
+// assuming a uniform hourly rate of baseCost / hours.
+func tieredAmount(tiers []storage.DiscountTier, hours float64, baseCost money.Money) money.Money {
+	if hours <= 0 {
+		return money.Zero(baseCost.Currency())
+	}
+
+	// 구간별 할인율을 시간 비중으로 가중 평균한 뒤 한 번만 곱해 반올림 오차를 줄임
+	weighted := 0.0
+	lower := 0.0
+	for _, tier := range tiers {
+		upper := hours
+		if tier.UpToHours != nil && *tier.UpToHours < upper {
+			upper = *tier.UpToHours
+		}
+		if upper > lower {
+			weighted += (upper - lower) / hours * (tier.DiscountPercent / 100.0)
+			lower = upper
+		}
+		if lower >= hours {
+			break
+		}
+	}
+
+	return baseCost.Mul(weighted)
+}
+
+// applySummaryDiscounts applies summary-scoped rules to the total of the
+// instances whose conditions match, after every instance-level discount.
+// The rules of each CSP are stacked on that CSP's instances first, then the
+// global rules on what is left of the whole total.
+func (c *CostCalculator) applySummaryDiscounts(summary *CostSummary, instances []*storage.InstanceState) {
+	if !c.pricingStorage.IsNewFormat() {
+		return
+	}
+
+	for _, cspName := range c.pricingStorage.CSPNames() {
+		c.forCSP(cspName).applySummaryScope(summary, instances, c.pricingStorage.CSPs[cspName].DiscountRules, cspName)
+	}
+	c.applySummaryScope(summary, instances, c.pricingStorage.GlobalDiscountRules, "")
+}
+
+// applySummaryScope stacks the summary rules of one scope (a CSP, or every
+// instance when cspName is empty) in a single pass, so that exclusive groups,
+// compound mode and the total discount cap act across the rules.
+func (c *CostCalculator) applySummaryScope(summary *CostSummary, instances []*storage.InstanceState, rules []storage.DiscountRule, cspName string) {
+	inScope := func(cost *InstanceCost) bool {
+		return cspName == "" || cost.CSP == cspName
+	}
+
+	scope := discountBasis{baseCost: money.Zero(summary.Currency), currency: summary.Currency, period: summary.Period}
+	for j := range summary.InstanceCosts {
+		if cost := &summary.InstanceCosts[j]; inScope(cost) {
+			scope.baseCost = scope.baseCost.Add(cost.FinalCost)
+			scope.hours += cost.TotalRunningHours
+		}
+	}
+	if !scope.baseCost.IsPositive() {
+		return
+	}
+
+	// 전역 규칙은 CSP 요약 할인이 적용된 뒤의 합계 기준. 조건별 대상 금액도 같은 비율로 줄임
+	shrink := 1.0
+	if cspName == "" {
+		shrink = summary.TotalFinalCost.Ratio(scope.baseCost)
+		scope.baseCost = summary.TotalFinalCost
+	}
+
+	candidates := make([]discountCandidate, 0, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Enabled || rule.Scope != storage.DiscountScopeSummary {
+			continue
+		}
+
+		from, to, err := rule.EffectiveWindow()
+		if err != nil {
+			continue
+		}
+		window := summary.Period
+		if !from.IsZero() && from.After(window.StartTime) {
+			window.StartTime = from
+		}
+		if !to.IsZero() && to.Before(window.EndTime) {
+			window.EndTime = to
+		}
+		if !window.EndTime.After(window.StartTime) {
+			continue
+		}
+
+		// 조건을 만족하는 인스턴스의 할인 후 비용 합계가 적용 대상
+		matched := discountBasis{baseCost: money.Zero(summary.Currency), currency: summary.Currency, period: summary.Period}
+		for j := range summary.InstanceCosts {
+			cost := &summary.InstanceCosts[j]
+			if !inScope(cost) || !c.forCSP(cost.CSP).evaluateDiscountRule(rule, cost, instances[j]) {
+				continue
+			}
+			matched.baseCost = matched.baseCost.Add(cost.FinalCost)
+			matched.hours += cost.TotalRunningHours
+		}
+		if !matched.baseCost.IsPositive() {
+			continue
+		}
+		matched.baseCost = matched.baseCost.Mul(shrink)
+
+		ratio := window.EndTime.Sub(window.StartTime).Hours() / summary.Period.EndTime.Sub(summary.Period.StartTime).Hours()
+		candidates = append(candidates, discountCandidate{rule: rule, ratio: ratio, window: window, matched: &matched})
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	var applied []DiscountDetail
+	applied, summary.SkippedSummaryDiscounts, _ = c.stackDiscounts(candidates, scope, nil, summary.SkippedSummaryDiscounts)
+
+	for _, detail := range applied {
+		// 반올림으로 남은 합계를 넘지 않도록 제한
+		detail.DiscountAmount = c.roundAmount(detail.DiscountAmount)
+		if detail.DiscountAmount.Cmp(summary.TotalFinalCost) > 0 {
+			detail.DiscountAmount = summary.TotalFinalCost
+			detail.Resolution += ", 남은 합계 비용으로 감액"
+		}
+		if !detail.DiscountAmount.IsPositive() {
+			continue
+		}
+		detail.CSP = cspName
+		summary.SummaryDiscounts = append(summary.SummaryDiscounts, detail)
+		summary.TotalSummaryDiscount = summary.TotalSummaryDiscount.Add(detail.DiscountAmount)
+		summary.TotalDiscount = summary.TotalDiscount.Add(detail.DiscountAmount)
+		summary.TotalFinalCost = summary.TotalFinalCost.Sub(detail.DiscountAmount)
+	}
+}
+
+// pickExclusiveWinners keeps only the largest standalone discount in every
+// exclusive group; the others are recorded as skipped.
+func (c *CostCalculator) pickExclusiveWinners(candidates []discountCandidate, basis discountBasis, skipped []DiscountDetail) ([]discountCandidate, []DiscountDetail) {
+	best := make(map[string]int)
+	bestAmount := make(map[string]money.Money)
+	for i, candidate := range candidates {
+		group := candidate.rule.ExclusiveGroup
+		if group == "" {
+			continue
+		}
+		amount := c.standaloneAmount(candidate, basis)
+		if _, exists := best[group]; !exists || amount.Cmp(bestAmount[group]) > 0 {
+			best[group] = i
+			bestAmount[group] = amount
+		}
+	}
+
+	winners := make([]discountCandidate, 0, len(candidates))
+	for i, candidate := range candidates {
+		group := candidate.rule.ExclusiveGroup
+		if group == "" || best[group] == i {
+			winners = append(winners, candidate)
+			continue
+		}
+
+		detail := c.discountDetail(candidate, c.stackMode(candidate.rule))
+		detail.Resolution = fmt.Sprintf("그룹 '%s'에서 '%s' 규칙이 더 큰 할인으로 선택되어 제외", group, candidates[best[group]].rule.Name)
+		skipped = append(skipped, detail)
+	}
+
+	return winners, skipped
+}
+
+// standaloneAmount is the discount a rule would give if applied alone
+func (c *CostCalculator) standaloneAmount(candidate discountCandidate, basis discountBasis) money.Money {
+	amount := c.ruleAmount(candidate, basis, storage.StackModeAdditive, basis.baseCost)
+	if limit, _ := c.ruleCap(candidate.rule, basis.currency); limit.IsPositive() && amount.Cmp(limit) > 0 {
+		amount = limit
+	}
+	return amount
+}
+
+// dropUnconvertible skips the rules whose fixed amount or cap, written in the
+// CSP default currency, has no exchange rate into the report currency.
+// Converting those at 1:1 would turn a ₩10,000 credit into $10,000.
+func (c *CostCalculator) dropUnconvertible(candidates []discountCandidate, currency string, skipped []DiscountDetail) ([]discountCandidate, []DiscountDetail) {
+	kept := make([]discountCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		rule := candidate.rule
+		var err error
+		if rule.Method == storage.DiscountMethodFixed {
+			_, err = c.fromDefaultCurrency(rule.DiscountAmount, currency)
+		}
+		if err == nil {
+			_, err = c.ruleCap(rule, currency)
+		}
+		if err == nil {
+			kept = append(kept, candidate)
+			continue
+		}
+
+		detail := c.discountDetail(candidate, c.stackMode(rule))
+		detail.Resolution = fmt.Sprintf("환율이 없어 제외 (%v)", err)
+		skipped = append(skipped, detail)
+	}
+	return kept, skipped
+}
+
+// ruleCap converts the rule's max_discount_amount, written in the CSP default
+// currency, into the report currency.
+func (c *CostCalculator) ruleCap(rule *storage.DiscountRule, currency string) (money.Money, error) {
+	if rule.MaxDiscountAmount <= 0 {
+		return money.Zero(currency), nil
+	}
+	return c.fromDefaultCurrency(rule.MaxDiscountAmount, currency)
+}
+
+// fromDefaultCurrency converts an amount written in the CSP default currency
+// (caps, fixed credits) into the report currency.
+func (c *CostCalculator) fromDefaultCurrency(amount float64, currency string) (money.Money, error) {
+	native := money.FromFloat(amount, c.pricingStorage.GetCSPCurrency(c.cspName()))
+	rate, err := c.pricingStorage.GetExchangeRate(native.Currency(), currency)
+	if err != nil {
+		return money.Zero(currency), err
+	}
+	return native.Convert(rate.Rate, currency), nil
+}
+
+func (c *CostCalculator) discountPolicy() storage.DiscountPolicy {
+	if c.pricingStorage.NewPricingSchema == nil {
+		return storage.DiscountPolicy{}
+	}
+	return c.pricingStorage.GlobalSettings.DiscountPolicy
+}
+
+func (c *CostCalculator) stackMode(rule *storage.DiscountRule) string {
+	if rule.StackMode != "" {
+		return rule.StackMode
+	}
+	if mode := c.discountPolicy().DefaultStackMode; mode != "" {
+		return mode
+	}
+	return storage.StackModeAdditive
+}
+
+func (c *CostCalculator) discountDetail(candidate discountCandidate, mode string) DiscountDetail {
+	return DiscountDetail{
+		RuleName:        candidate.rule.Name,
+		DiscountPercent: candidate.rule.DiscountPercent,
+		EffectiveFrom:   candidate.window.StartTime,
+		EffectiveTo:     candidate.window.EndTime,
+		EffectiveRatio:  candidate.ratio,
+		Priority:        candidate.rule.Priority,
+		StackMode:       mode,
+		ExclusiveGroup:  candidate.rule.ExclusiveGroup,
+		Method:          candidate.rule.Method,
+		Scope:           candidate.rule.Scope,
+	}
+}
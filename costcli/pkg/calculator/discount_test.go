@@ -0,0 +1,147 @@
+package calculator
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"costcli/pkg/money"
+	"costcli/pkg/storage"
+)
+
+func TestTieredAmount(t *testing.T) {
+	hours := func(h float64) *float64 { return &h }
+	tiers := []storage.DiscountTier{
+		{UpToHours: hours(100), DiscountPercent: 0},
+		{UpToHours: hours(300), DiscountPercent: 10},
+		{DiscountPercent: 20},
+	}
+
+	tests := []struct {
+		name  string
+		tiers []storage.DiscountTier
+		hours float64
+		want  float64
+	}{
+		{"no hours", tiers, 0, 0},
+		{"first tier only", tiers, 50, 0},
+		{"into the second tier", tiers, 200, 1000},
+		{"up to the end of the second tier", tiers, 300, 2000},
+		{"into the open tier", tiers, 500, 2000 + 4000},
+		{"hours beyond the last bounded tier are not discounted", []storage.DiscountTier{{UpToHours: hours(100), DiscountPercent: 10}}, 200, 1000},
+		{"single open tier", []storage.DiscountTier{{DiscountPercent: 15}}, 40, 600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 시간당 100원 기준
+			got := tieredAmount(tt.tiers, tt.hours, money.FromFloat(tt.hours*100, "KRW"))
+			if want := money.FromFloat(tt.want, "KRW"); got.Cmp(want) != 0 {
+				t.Errorf("tieredAmount(%v hours) = %s, want %s", tt.hours, got, want)
+			}
+		})
+	}
+}
+
+func TestStackDiscounts(t *testing.T) {
+	percent := func(p float64) *float64 { return &p }
+	rule := func(name string, discount float64) storage.DiscountRule {
+		return storage.DiscountRule{ID: name, Name: name, DiscountPercent: discount, Enabled: true}
+	}
+	compound := func(r storage.DiscountRule) storage.DiscountRule {
+		r.StackMode = storage.StackModeCompound
+		return r
+	}
+	capped := func(r storage.DiscountRule, amount float64) storage.DiscountRule {
+		r.MaxDiscountAmount = amount
+		return r
+	}
+	group := func(r storage.DiscountRule, name string) storage.DiscountRule {
+		r.ExclusiveGroup = name
+		return r
+	}
+	priority := func(r storage.DiscountRule, p int) storage.DiscountRule {
+		r.Priority = p
+		return r
+	}
+
+	tests := []struct {
+		name        string
+		rules       []storage.DiscountRule
+		maxTotal    *float64
+		want        float64
+		wantApplied []string
+		wantSkipped []string
+	}{
+		{"additive rules discount the base cost", []storage.DiscountRule{rule("a", 10), rule("b", 20)}, nil, 3000, []string{"a", "b"}, nil},
+		{"compound rule discounts what is left", []storage.DiscountRule{rule("a", 10), compound(rule("b", 20))}, nil, 1000 + 1800, []string{"a", "b"}, nil},
+		{"priority orders compound rules", []storage.DiscountRule{priority(compound(rule("b", 20)), 2), priority(rule("a", 50), 1)}, nil, 5000 + 1000, []string{"a", "b"}, nil},
+		{"rule cap", []storage.DiscountRule{capped(rule("a", 50), 2000), rule("b", 10)}, nil, 2000 + 1000, []string{"a", "b"}, nil},
+		{
+			"largest rule of an exclusive group wins",
+			[]storage.DiscountRule{group(rule("a", 10), "promo"), group(rule("b", 30), "promo"), rule("c", 5)},
+			nil, 3000 + 500, []string{"b", "c"}, []string{"a"},
+		},
+		{
+			"exclusive groups compare capped amounts",
+			[]storage.DiscountRule{group(capped(rule("a", 50), 1000), "promo"), group(rule("b", 20), "promo")},
+			nil, 2000, []string{"b"}, []string{"a"},
+		},
+		{"total cap reduces the last rule", []storage.DiscountRule{rule("a", 20), rule("b", 20)}, percent(25), 2500, []string{"a", "b"}, nil},
+		{"rules past the total cap are skipped", []storage.DiscountRule{rule("a", 10), rule("b", 5)}, percent(10), 1000, []string{"a"}, []string{"b"}},
+		{"discount never exceeds the base cost", []storage.DiscountRule{rule("a", 80), rule("b", 50)}, nil, 10000, []string{"a", "b"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calc := newSchemaCalculator(storage.CSPProvider{}, storage.GlobalSettings{
+				DiscountPolicy: storage.DiscountPolicy{MaxTotalDiscountPercent: tt.maxTotal},
+			})
+
+			candidates := make([]discountCandidate, len(tt.rules))
+			for i := range tt.rules {
+				candidates[i] = discountCandidate{rule: &tt.rules[i], ratio: 1}
+			}
+			start := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+			basis := discountBasis{
+				baseCost: money.FromFloat(10000, "KRW"),
+				hours:    100,
+				currency: "KRW",
+				period:   TimePeriod{StartTime: start, EndTime: start.AddDate(0, 1, 0)},
+			}
+
+			applied, skipped, total := calc.stackDiscounts(candidates, basis, nil, nil)
+			if want := money.FromFloat(tt.want, "KRW"); total.Cmp(want) != 0 {
+				t.Errorf("total discount = %s, want %s", total, want)
+			}
+			if got := ruleNames(applied); !slices.Equal(got, tt.wantApplied) {
+				t.Errorf("applied = %v, want %v", got, tt.wantApplied)
+			}
+			if got := ruleNames(skipped); !slices.Equal(got, tt.wantSkipped) {
+				t.Errorf("skipped = %v, want %v", got, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func ruleNames(details []DiscountDetail) []string {
+	var names []string
+	for _, detail := range details {
+		names = append(names, detail.RuleName)
+	}
+	return names
+}
+
+func TestMatchesConditionCompilesOnce(t *testing.T) {
+	calc := newSchemaCalculator(storage.CSPProvider{}, storage.GlobalSettings{})
+
+	if !calc.compareStrings("web-01", "matches", "^web-") || calc.compareStrings("db-01", "matches", "^web-") {
+		t.Error("matches ^web- should match only web-01")
+	}
+	if calc.compareStrings("web-01", "matches", "(") {
+		t.Error("invalid pattern should not match")
+	}
+	if len(calc.patterns) != 2 || calc.patterns["("] != nil {
+		t.Errorf("patterns = %v, want one compiled and one invalid entry", calc.patterns)
+	}
+}
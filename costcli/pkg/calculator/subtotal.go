@@ -0,0 +1,70 @@
+package calculator
+
+import (
+	"sort"
+
+	"costcli/pkg/money"
+)
+
+// CSPSubtotal is the cost of one CSP's instances in the report currency.
+// Summary discounts of that CSP are included; global ones are not.
+type CSPSubtotal struct {
+	CSP       string      `json:"csp"`
+	Instances int         `json:"instances"`
+	BaseCost  money.Money `json:"base_cost"`
+	Discount  money.Money `json:"discount"`
+	FinalCost money.Money `json:"final_cost"`
+
+	// CSP 기본 통화가 보고 통화와 다르면 해당 통화로 환산한 최종 비용
+	NativeFinalCost money.Money `json:"native_final_cost,omitzero"`
+	ExchangeRate    float64     `json:"exchange_rate,omitempty"`
+}
+
+// cspSubtotals groups the instance costs and CSP summary discounts by CSP
+func (c *CostCalculator) cspSubtotals(summary *CostSummary) []CSPSubtotal {
+	byCSP := make(map[string]*CSPSubtotal)
+	subtotal := func(name string) *CSPSubtotal {
+		if _, exists := byCSP[name]; !exists {
+			byCSP[name] = &CSPSubtotal{
+				CSP:       name,
+				BaseCost:  money.Zero(summary.Currency),
+				Discount:  money.Zero(summary.Currency),
+				FinalCost: money.Zero(summary.Currency),
+			}
+		}
+		return byCSP[name]
+	}
+
+	for _, cost := range summary.InstanceCosts {
+		total := subtotal(cost.CSP)
+		total.Instances++
+		total.BaseCost = total.BaseCost.Add(cost.BaseCost)
+		total.Discount = total.Discount.Add(cost.TotalDiscount)
+		total.FinalCost = total.FinalCost.Add(cost.FinalCost)
+	}
+	for _, detail := range summary.SummaryDiscounts {
+		if detail.CSP == "" {
+			continue
+		}
+		total := subtotal(detail.CSP)
+		total.Discount = total.Discount.Add(detail.DiscountAmount)
+		total.FinalCost = total.FinalCost.Sub(detail.DiscountAmount)
+	}
+
+	subtotals := make([]CSPSubtotal, 0, len(byCSP))
+	for name, total := range byCSP {
+		native := c.pricingStorage.GetCSPCurrency(name)
+		if native != summary.Currency {
+			if rate, err := c.pricingStorage.GetExchangeRate(summary.Currency, native); err == nil {
+				total.ExchangeRate = rate.Rate
+				total.NativeFinalCost = c.forCSP(name).roundAmount(total.FinalCost.Convert(rate.Rate, native))
+			}
+		}
+		subtotals = append(subtotals, *total)
+	}
+	sort.Slice(subtotals, func(i, j int) bool {
+		return subtotals[i].CSP < subtotals[j].CSP
+	})
+
+	return subtotals
+}
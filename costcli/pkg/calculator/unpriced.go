@@ -0,0 +1,119 @@
+package calculator
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"costcli/pkg/storage"
+)
+
+// UnpricedFlavorError reports a flavor that has no price in the pricing file
+type UnpricedFlavorError struct {
+	FlavorID string
+}
+
+func (e *UnpricedFlavorError) Error() string {
+	return fmt.Sprintf("flavor %s에 대한 가격 정보를 찾을 수 없습니다", e.FlavorID)
+}
+
+// UnpricedInstance is an instance left out of the totals because its flavor
+// has no price and no fallback price could be derived
+type UnpricedInstance struct {
+	InstanceID   string `json:"instance_id"`
+	InstanceName string `json:"instance_name"`
+	CSP          string `json:"csp,omitempty"`
+	FlavorID     string `json:"flavor_id"`
+	FlavorName   string `json:"flavor_name"`
+	Region       string `json:"region,omitempty"`
+}
+
+// SetFlavorStorage sets the flavor catalog synced from Nova, used to name
+// and estimate flavors missing from the pricing file
+func (c *CostCalculator) SetFlavorStorage(flavorStorage *storage.FlavorStorage) {
+	c.flavorStorage = flavorStorage
+}
+
+// SetStrict makes a missing flavor price fail the whole calculation instead
+// of estimating it or leaving the instance out
+func (c *CostCalculator) SetStrict(strict bool) {
+	c.strict = strict
+}
+
+// flavorPriceAt returns the flavor price in effect at the given time. Outside
+// strict mode a missing price is estimated from the flavor specs when the CSP
+// has fallback pricing; estimated reports whether that happened.
+func (c *CostCalculator) flavorPriceAt(flavorID, region string, at time.Time) (*storage.FlavorPrice, bool, error) {
+	if price, exists := c.pricingStorage.GetFlavorPriceAt(c.cspName(), region, flavorID, at); exists {
+		return price, false, nil
+	}
+	if !c.strict {
+		if price, exists := c.pricingStorage.GetFallbackPrice(c.cspName(), flavorID, c.flavorSpec(flavorID, region)); exists {
+			return price, true, nil
+		}
+	}
+	return nil, false, &UnpricedFlavorError{FlavorID: flavorID}
+}
+
+// flavorSpec returns the flavor specs from the pricing file, else from the
+// synced flavor catalog
+func (c *CostCalculator) flavorSpec(flavorID, region string) *storage.FlavorSpec {
+	if spec, exists := c.pricingStorage.GetFlavorSpec(c.cspName(), flavorID); exists {
+		return spec
+	}
+	if c.flavorStorage != nil {
+		if spec, exists := c.flavorStorage.GetFlavor(region, flavorID); exists {
+			return spec
+		}
+	}
+	return nil
+}
+
+// flavorName returns the flavor name from the pricing file, else from the
+// synced flavor catalog, else the flavor ID
+func (c *CostCalculator) flavorName(flavorID, region string) string {
+	name := c.pricingStorage.GetFlavorName(c.cspName(), flavorID)
+	if name != flavorID || c.flavorStorage == nil {
+		return name
+	}
+	if spec, exists := c.flavorStorage.GetFlavor(region, flavorID); exists && spec.Name != "" {
+		return spec.Name
+	}
+	return name
+}
+
+// unpricedWarnings summarizes the excluded and estimated flavors per flavor
+func unpricedWarnings(unpriced []UnpricedInstance, estimated map[string]int, names map[string]string) []string {
+	excluded := make(map[string]int)
+	for _, instance := range unpriced {
+		excluded[instance.FlavorID]++
+		names[instance.FlavorID] = instance.FlavorName
+	}
+
+	var warnings []string
+	for _, flavorID := range sortedFlavorIDs(excluded) {
+		warnings = append(warnings, fmt.Sprintf("flavor %s의 가격 정보가 없어 인스턴스 %d개를 비용에서 제외했습니다",
+			flavorLabel(flavorID, names[flavorID]), excluded[flavorID]))
+	}
+	for _, flavorID := range sortedFlavorIDs(estimated) {
+		warnings = append(warnings, fmt.Sprintf("flavor %s의 가격 정보가 없어 vCPU/메모리 단가로 추정했습니다 (인스턴스 %d개)",
+			flavorLabel(flavorID, names[flavorID]), estimated[flavorID]))
+	}
+	return warnings
+}
+
+func sortedFlavorIDs(counts map[string]int) []string {
+	ids := make([]string, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func flavorLabel(flavorID, name string) string {
+	if name == "" || name == flavorID {
+		return flavorID
+	}
+	return fmt.Sprintf("%s (%s)", name, flavorID)
+}
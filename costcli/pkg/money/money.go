@@ -0,0 +1,236 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Scale is the number of fixed decimal places kept internally
+const Scale = 6
+
+const unitsPerOne = 1_000_000
+
+// Rounding modes
+const (
+	RoundHalfUp = "half_up"
+	RoundUp     = "up"
+	RoundDown   = "down"
+)
+
+// Money is a fixed-point amount in a currency. Amounts are stored as an
+// integer number of 10^-6 currency units, so sums are exact and independent
+// of the order in which they are added.
+type Money struct {
+	units    int64
+	currency string
+}
+
+// Zero returns a zero amount in the currency
+func Zero(currency string) Money {
+	return Money{currency: currency}
+}
+
+// FromFloat converts a float amount, rounding half away from zero to the
+// internal scale.
+func FromFloat(amount float64, currency string) Money {
+	return Money{units: int64(math.Round(amount * unitsPerOne)), currency: currency}
+}
+
+// Parse reads a decimal string such as "1234.56"
+func Parse(amount, currency string) (Money, error) {
+	rat, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok {
+		return Money{}, fmt.Errorf("금액 형식 오류: %q", amount)
+	}
+	rat.Mul(rat, big.NewRat(unitsPerOne, 1))
+	if !rat.IsInt() {
+		return Money{}, fmt.Errorf("소수점 %d자리를 초과하는 금액입니다: %q", Scale, amount)
+	}
+	if !rat.Num().IsInt64() {
+		return Money{}, fmt.Errorf("금액 범위를 초과했습니다: %q", amount)
+	}
+	return Money{units: rat.Num().Int64(), currency: currency}, nil
+}
+
+// Currency returns the currency code
+func (m Money) Currency() string {
+	return m.currency
+}
+
+// Float64 returns the amount as a float for ratios and display
+func (m Money) Float64() float64 {
+	return float64(m.units) / unitsPerOne
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.units == 0
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m.units > 0
+}
+
+// Cmp compares two amounts and returns -1, 0 or +1
+func (m Money) Cmp(o Money) int {
+	m.mustMatch(o)
+	switch {
+	case m.units < o.units:
+		return -1
+	case m.units > o.units:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Add returns m + o
+func (m Money) Add(o Money) Money {
+	currency := m.mustMatch(o)
+	return Money{units: m.units + o.units, currency: currency}
+}
+
+// Sub returns m - o
+func (m Money) Sub(o Money) Money {
+	currency := m.mustMatch(o)
+	return Money{units: m.units - o.units, currency: currency}
+}
+
+// Mul multiplies by a factor (hours, percentages, exchange rates) and rounds
+// half away from zero to the internal scale.
+func (m Money) Mul(factor float64) Money {
+	return Money{units: int64(math.Round(float64(m.units) * factor)), currency: m.currency}
+}
+
+// Convert multiplies by an exchange rate and changes the currency
+func (m Money) Convert(rate float64, currency string) Money {
+	converted := m.Mul(rate)
+	converted.currency = currency
+	return converted
+}
+
+// Ratio returns m / o, or 0 when o is zero
+func (m Money) Ratio(o Money) float64 {
+	if o.units == 0 {
+		return 0
+	}
+	return float64(m.units) / float64(o.units)
+}
+
+// Min returns the smaller amount
+func Min(a, b Money) Money {
+	if a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
+}
+
+// Round rounds to the given number of decimals (negative rounds to tens,
+// hundreds, ...) using mode.
+func (m Money) Round(decimals int, mode string) Money {
+	if decimals >= Scale {
+		return m
+	}
+
+	step := int64(1)
+	for i := decimals; i < Scale; i++ {
+		step *= 10
+	}
+
+	quotient := m.units / step
+	remainder := m.units % step
+	if remainder == 0 {
+		return m
+	}
+
+	sign := int64(1)
+	if m.units < 0 {
+		sign = -1
+		remainder = -remainder
+	}
+
+	switch mode {
+	case RoundUp:
+		quotient += sign
+	case RoundDown:
+	default:
+		if remainder*2 >= step {
+			quotient += sign
+		}
+	}
+
+	return Money{units: quotient * step, currency: m.currency}
+}
+
+// DefaultDecimals returns the usual minor unit of a currency
+func DefaultDecimals(currency string) int {
+	switch currency {
+	case "KRW", "JPY":
+		return 0
+	default:
+		return 2
+	}
+}
+
+// String formats the exact amount with at least two decimals, e.g. "1234.50"
+func (m Money) String() string {
+	units := m.units
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+
+	fraction := fmt.Sprintf("%06d", units%unitsPerOne)
+	fraction = strings.TrimRight(fraction, "0")
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+
+	return fmt.Sprintf("%s%d.%s", sign, units/unitsPerOne, fraction)
+}
+
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON writes the amount as a decimal string so no precision is lost
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.String(), Currency: m.currency})
+}
+
+// UnmarshalJSON reads {"amount": "1234.50", "currency": "KRW"}
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("금액 JSON 파싱 실패: %w", err)
+	}
+
+	parsed, err := Parse(raw.Amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// mustMatch returns the shared currency. A zero value without currency
+// adopts the other side's currency; a non-zero amount without currency does
+// not match any currency.
+func (m Money) mustMatch(o Money) string {
+	switch {
+	case m.currency == o.currency:
+		return m.currency
+	case m.currency == "" && m.units == 0:
+		return o.currency
+	case o.currency == "" && o.units == 0:
+		return m.currency
+	default:
+		panic(fmt.Sprintf("money: 통화가 다른 금액끼리 연산할 수 없습니다 (%s, %s)", m.currency, o.currency))
+	}
+}
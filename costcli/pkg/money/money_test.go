@@ -0,0 +1,68 @@
+package money
+
+import "testing"
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		decimals int
+		mode     string
+		want     string
+	}{
+		{"half up rounds half away from zero", "12.345", 2, RoundHalfUp, "12.35"},
+		{"half up keeps lower half", "12.344999", 2, RoundHalfUp, "12.34"},
+		{"half up negative", "-12.345", 2, RoundHalfUp, "-12.35"},
+		{"up rounds away from zero", "12.341", 2, RoundUp, "12.35"},
+		{"up negative", "-12.341", 2, RoundUp, "-12.35"},
+		{"down truncates", "12.349", 2, RoundDown, "12.34"},
+		{"down negative", "-12.349", 2, RoundDown, "-12.34"},
+		{"unknown mode is half up", "0.5", 0, "", "1.00"},
+		{"exact amount unchanged", "12.30", 2, RoundUp, "12.30"},
+		{"won", "1234.5", 0, RoundHalfUp, "1235.00"},
+		{"negative decimals round to tens", "1234.5", -1, RoundHalfUp, "1230.00"},
+		{"negative decimals up", "1201", -2, RoundUp, "1300.00"},
+		{"negative decimals down", "1299", -2, RoundDown, "1200.00"},
+		{"six decimals is the internal scale", "0.123456", 6, RoundUp, "0.123456"},
+		{"more decimals than the scale", "0.123456", 8, RoundDown, "0.123456"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := Parse(tt.amount, "KRW")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := amount.Round(tt.decimals, tt.mode)
+			if got.String() != tt.want || got.Currency() != "KRW" {
+				t.Errorf("Round(%s, %d, %q) = %s %s, want %s KRW", tt.amount, tt.decimals, tt.mode, got, got.Currency(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrencyMatching(t *testing.T) {
+	krw := FromFloat(100, "KRW")
+
+	// 통화가 없는 0은 상대 통화를 따름
+	if got := (Money{}).Add(krw); got.Currency() != "KRW" || got.String() != "100.00" {
+		t.Errorf("zero + 100 KRW = %s %s, want 100.00 KRW", got, got.Currency())
+	}
+	if got := krw.Sub(Money{}); got.Currency() != "KRW" || got.String() != "100.00" {
+		t.Errorf("100 KRW - zero = %s %s, want 100.00 KRW", got, got.Currency())
+	}
+
+	mustPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: want panic", name)
+			}
+		}()
+		f()
+	}
+	mustPanic("KRW + USD", func() { krw.Add(FromFloat(1, "USD")) })
+	// 금액이 있는데 통화가 없으면 어느 통화와도 섞을 수 없음
+	mustPanic("KRW + amount without currency", func() { krw.Add(FromFloat(1, "")) })
+	mustPanic("compare amount without currency", func() { FromFloat(1, "").Cmp(krw) })
+}
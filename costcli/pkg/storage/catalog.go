@@ -0,0 +1,227 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// PriceVersion is a superseded price of an instance type. It was in effect
+// from the previous version's EffectiveTo (or always, for the first one) until
+// EffectiveTo; the instance type's pricing applies from the last EffectiveTo.
+type PriceVersion struct {
+	EffectiveTo   string                        `json:"effective_to"`
+	Pricing       map[string]Pricing            `json:"pricing"`
+	RegionPricing map[string]map[string]Pricing `json:"region_pricing,omitempty"`
+}
+
+// ParseEffectiveTime parses a catalog date, either RFC3339 or YYYY-MM-DD (UTC)
+func ParseEffectiveTime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("날짜 형식 오류 (%s): RFC3339 또는 YYYY-MM-DD 형식이어야 합니다", value)
+	}
+	return parsed, nil
+}
+
+// PricingAt returns the prices in effect at the given time. A zero time or a
+// time after every recorded change returns the current pricing.
+func (t *InstanceType) PricingAt(at time.Time) map[string]Pricing {
+	return t.RegionalPricingAt("", at)
+}
+
+// RegionalPricingAt is PricingAt for a region: the region's override from
+// region_pricing when the price version lists one, else the common prices.
+func (t *InstanceType) RegionalPricingAt(region string, at time.Time) map[string]Pricing {
+	pricing, regional := t.Pricing, t.RegionPricing
+	if !at.IsZero() {
+		for _, version := range t.sortedHistory() {
+			effectiveTo, err := ParseEffectiveTime(version.EffectiveTo)
+			if err != nil {
+				continue
+			}
+			if at.Before(effectiveTo) {
+				pricing, regional = version.Pricing, version.RegionPricing
+				break
+			}
+		}
+	}
+	if override, exists := regional[region]; exists && region != "" {
+		return override
+	}
+	return pricing
+}
+
+// AvailableIn reports whether the flavor is offered in the region. A flavor
+// without an availability list, or an unknown region, is not checked.
+func (t *InstanceType) AvailableIn(region string) bool {
+	if region == "" || len(t.Availability) == 0 {
+		return true
+	}
+	return slices.Contains(t.Availability, region)
+}
+
+// PriceChanges returns the times at which the instance type's price changed
+func (t *InstanceType) PriceChanges() []time.Time {
+	var changes []time.Time
+	for _, version := range t.sortedHistory() {
+		if effectiveTo, err := ParseEffectiveTime(version.EffectiveTo); err == nil {
+			changes = append(changes, effectiveTo)
+		}
+	}
+	return changes
+}
+
+func (t *InstanceType) sortedHistory() []PriceVersion {
+	history := append([]PriceVersion(nil), t.PriceHistory...)
+	sort.SliceStable(history, func(i, j int) bool {
+		a, _ := ParseEffectiveTime(history[i].EffectiveTo)
+		b, _ := ParseEffectiveTime(history[j].EffectiveTo)
+		return a.Before(b)
+	})
+	return history
+}
+
+// GetPriceChanges returns when the price of a flavor changed in a CSP's
+// catalog. The legacy format has no price history.
+func (p *PricingStorage) GetPriceChanges(cspName, flavorID string) []time.Time {
+	if p.NewPricingSchema == nil {
+		return nil
+	}
+	if csp, exists := p.CSPs[p.cspOrDefault(cspName)]; exists {
+		if instanceType, exists := csp.InstanceTypes[flavorID]; exists {
+			return instanceType.PriceChanges()
+		}
+	}
+	return nil
+}
+
+// CatalogEntry is the price of one flavor in one currency
+type CatalogEntry struct {
+	CSP        string  `json:"csp,omitempty"`
+	Region     string  `json:"region,omitempty"` // 리전별 가격인 경우
+	FlavorID   string  `json:"flavor_id"`
+	FlavorName string  `json:"flavor_name"`
+	Currency   string  `json:"currency"`
+	Pricing    Pricing `json:"pricing"`
+}
+
+// CatalogAt returns every flavor price in effect at the given time, sorted by
+// CSP, flavor and currency. A zero time returns the current prices.
+func (p *PricingStorage) CatalogAt(at time.Time) []CatalogEntry {
+	var entries []CatalogEntry
+
+	if p.NewPricingSchema == nil {
+		for flavorID, price := range p.Flavors {
+			entries = append(entries, CatalogEntry{
+				FlavorID:   flavorID,
+				FlavorName: flavorID,
+				Currency:   price.Currency,
+				Pricing: Pricing{
+					Hourly:         price.HourlyPrice,
+					Monthly:        price.MonthlyPrice,
+					Yearly:         price.YearlyPrice,
+					ShutdownHourly: price.ShutdownHourlyPrice,
+					StorageHourly:  price.StorageHourlyPrice,
+				},
+			})
+		}
+	} else {
+		for cspName, csp := range p.CSPs {
+			for flavorID, instanceType := range csp.InstanceTypes {
+				for currency, pricing := range instanceType.PricingAt(at) {
+					entries = append(entries, CatalogEntry{
+						CSP:        cspName,
+						FlavorID:   flavorID,
+						FlavorName: instanceType.Name,
+						Currency:   currency,
+						Pricing:    pricing,
+					})
+				}
+				for _, region := range sortedKeys(instanceType.RegionPricing) {
+					for currency, pricing := range instanceType.RegionalPricingAt(region, at) {
+						entries = append(entries, CatalogEntry{
+							CSP:        cspName,
+							Region:     region,
+							FlavorID:   flavorID,
+							FlavorName: instanceType.Name,
+							Currency:   currency,
+							Pricing:    pricing,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key() < entries[j].key()
+	})
+	return entries
+}
+
+func (e CatalogEntry) key() string {
+	return e.CSP + "/" + e.FlavorID + "/" + e.Region + "/" + e.Currency
+}
+
+// Price diff kinds
+const (
+	PriceDiffAdded   = "added"
+	PriceDiffRemoved = "removed"
+	PriceDiffChanged = "changed"
+)
+
+// PriceDiff is a difference between two catalog versions
+type PriceDiff struct {
+	Kind   string        `json:"kind"`
+	Entry  CatalogEntry  `json:"entry"`
+	Before *CatalogEntry `json:"before,omitempty"`
+}
+
+// DiffCatalogs compares two catalog versions and returns the flavor prices
+// that were added, removed or changed from old to new.
+func DiffCatalogs(old, new []CatalogEntry) []PriceDiff {
+	oldByKey := make(map[string]CatalogEntry, len(old))
+	for _, entry := range old {
+		oldByKey[entry.key()] = entry
+	}
+	newByKey := make(map[string]bool, len(new))
+
+	var diffs []PriceDiff
+	for _, entry := range new {
+		newByKey[entry.key()] = true
+		before, exists := oldByKey[entry.key()]
+		switch {
+		case !exists:
+			diffs = append(diffs, PriceDiff{Kind: PriceDiffAdded, Entry: entry})
+		case !samePricing(before.Pricing, entry.Pricing):
+			diffs = append(diffs, PriceDiff{Kind: PriceDiffChanged, Entry: entry, Before: &before})
+		}
+	}
+	for _, entry := range old {
+		if !newByKey[entry.key()] {
+			diffs = append(diffs, PriceDiff{Kind: PriceDiffRemoved, Entry: entry})
+		}
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Entry.key() < diffs[j].Entry.key()
+	})
+	return diffs
+}
+
+func samePricing(a, b Pricing) bool {
+	return a.Hourly == b.Hourly && a.Monthly == b.Monthly && a.Yearly == b.Yearly &&
+		sameOptional(a.ShutdownHourly, b.ShutdownHourly) && sameOptional(a.StorageHourly, b.StorageHourly)
+}
+
+func sameOptional(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
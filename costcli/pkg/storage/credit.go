@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Credit is a prepaid credit or commitment balance that is consumed by usage
+// cost while it is valid.
+type Credit struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	Amount    float64     `json:"amount"`
+	Currency  string      `json:"currency"`
+	ValidFrom time.Time   `json:"valid_from"`
+	ValidTo   *time.Time  `json:"valid_to,omitempty"`
+	Scope     CreditScope `json:"scope,omitzero"`
+}
+
+// CreditScope limits which instances consume a credit. Empty lists match all.
+type CreditScope struct {
+	Regions     []string `json:"regions,omitempty"`
+	FlavorIDs   []string `json:"flavor_ids,omitempty"`
+	InstanceIDs []string `json:"instance_ids,omitempty"`
+}
+
+// Matches reports whether an instance is covered by the scope
+func (s CreditScope) Matches(instance *InstanceState) bool {
+	if len(s.Regions) > 0 && !slices.Contains(s.Regions, instance.Region) {
+		return false
+	}
+	if len(s.FlavorIDs) > 0 && !slices.Contains(s.FlavorIDs, instance.FlavorID) {
+		return false
+	}
+	if len(s.InstanceIDs) > 0 && !slices.Contains(s.InstanceIDs, instance.ID) {
+		return false
+	}
+	return true
+}
+
+// IsValidAt reports whether the credit can be consumed at t
+func (c *Credit) IsValidAt(t time.Time) bool {
+	if t.Before(c.ValidFrom) {
+		return false
+	}
+	return c.ValidTo == nil || t.Before(*c.ValidTo)
+}
+
+type CreditStorage struct {
+	Credits []Credit `json:"credits"`
+}
+
+func NewCreditStorage() *CreditStorage {
+	return &CreditStorage{
+		Credits: []Credit{},
+	}
+}
+
+// CreditFilePath returns the credit ledger stored next to the pricing file
+func CreditFilePath(priceFile string) string {
+	return filepath.Join(filepath.Dir(priceFile), "credits.json")
+}
+
+func (s *CreditStorage) LoadFromFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // 크레딧 원장이 없으면 빈 목록으로 시작
+		}
+		return fmt.Errorf("파일 읽기 실패: %w", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return fmt.Errorf("JSON 파싱 실패: %w", err)
+	}
+
+	return nil
+}
+
+func (s *CreditStorage) SaveToFile(filename string) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("디렉토리 생성 실패: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSON 마샬링 실패: %w", err)
+	}
+
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("파일 쓰기 실패: %w", err)
+	}
+
+	return nil
+}
+
+// AddCredit appends a credit, rejecting duplicate IDs
+func (s *CreditStorage) AddCredit(credit Credit) error {
+	for _, existing := range s.Credits {
+		if existing.ID == credit.ID {
+			return fmt.Errorf("이미 존재하는 크레딧 ID입니다: %s", credit.ID)
+		}
+	}
+	s.Credits = append(s.Credits, credit)
+	return nil
+}
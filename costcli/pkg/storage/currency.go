@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExchangeRate describes how an amount in From is converted into To.
+// Via is set when the rate was triangulated through a third currency.
+type ExchangeRate struct {
+	From        string  `json:"from"`
+	To          string  `json:"to"`
+	Rate        float64 `json:"rate"`
+	Via         string  `json:"via,omitempty"`
+	LastUpdated string  `json:"last_updated,omitempty"`
+}
+
+// parseRateKey splits a conversion key such as "USD_to_KRW" into its currencies.
+func parseRateKey(key string) (string, string, bool) {
+	parts := strings.Split(key, "_to_")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return strings.ToUpper(parts[0]), strings.ToUpper(parts[1]), true
+}
+
+// conversionRates returns the configured rates indexed by [from][to].
+func (p *PricingStorage) conversionRates() map[string]map[string]float64 {
+	rates := make(map[string]map[string]float64)
+	if p.NewPricingSchema == nil {
+		return rates
+	}
+
+	for key, rate := range p.GlobalSettings.CurrencyConversion.Rates {
+		from, to, ok := parseRateKey(key)
+		if !ok || rate <= 0 {
+			continue
+		}
+		if rates[from] == nil {
+			rates[from] = make(map[string]float64)
+		}
+		rates[from][to] = rate
+	}
+
+	return rates
+}
+
+// pairRate returns a direct or inverse rate between two currencies.
+func pairRate(rates map[string]map[string]float64, from, to string) (float64, bool) {
+	if rate, exists := rates[from][to]; exists {
+		return rate, true
+	}
+	if rate, exists := rates[to][from]; exists {
+		return 1 / rate, true
+	}
+	return 0, false
+}
+
+// GetExchangeRate returns the rate converting from into to using
+// global_settings.currency_conversion. Direct pairs are preferred, then the
+// inverse of the opposite pair, then triangulation through a common currency.
+func (p *PricingStorage) GetExchangeRate(from, to string) (*ExchangeRate, error) {
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+
+	lastUpdated := ""
+	if p.NewPricingSchema != nil {
+		lastUpdated = p.GlobalSettings.CurrencyConversion.LastUpdated
+	}
+
+	if from == to {
+		return &ExchangeRate{From: from, To: to, Rate: 1, LastUpdated: lastUpdated}, nil
+	}
+
+	rates := p.conversionRates()
+	if rate, ok := pairRate(rates, from, to); ok {
+		return &ExchangeRate{From: from, To: to, Rate: rate, LastUpdated: lastUpdated}, nil
+	}
+
+	// 공통 통화를 경유하는 환율 계산 (결과가 항상 같도록 통화 코드 순으로 탐색)
+	candidates := make(map[string]bool)
+	for base, targets := range rates {
+		candidates[base] = true
+		for quote := range targets {
+			candidates[quote] = true
+		}
+	}
+	via := make([]string, 0, len(candidates))
+	for currency := range candidates {
+		if currency != from && currency != to {
+			via = append(via, currency)
+		}
+	}
+	sort.Strings(via)
+
+	for _, currency := range via {
+		first, ok := pairRate(rates, from, currency)
+		if !ok {
+			continue
+		}
+		second, ok := pairRate(rates, currency, to)
+		if !ok {
+			continue
+		}
+		return &ExchangeRate{From: from, To: to, Rate: first * second, Via: currency, LastUpdated: lastUpdated}, nil
+	}
+
+	return nil, fmt.Errorf("%s → %s 환율 정보를 찾을 수 없습니다", from, to)
+}
+
+// GetDefaultCurrency returns the default currency of the default CSP.
+func (p *PricingStorage) GetDefaultCurrency() string {
+	return p.GetCSPCurrency("")
+}
+
+// GetCSPCurrency returns the default currency of a CSP (the default CSP when
+// empty).
+func (p *PricingStorage) GetCSPCurrency(cspName string) string {
+	if p.NewPricingSchema != nil {
+		if csp, exists := p.CSPs[p.cspOrDefault(cspName)]; exists && csp.DefaultCurrency != "" {
+			return csp.DefaultCurrency
+		}
+	}
+
+	for _, price := range p.Flavors {
+		if price.Currency != "" {
+			return price.Currency
+		}
+	}
+
+	return "KRW"
+}
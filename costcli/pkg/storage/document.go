@@ -0,0 +1,558 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PricingDocument edits a pricing file as a generic JSON tree so that fields
+// unknown to PricingStorage survive a round trip. Keys are written in sorted
+// order.
+type PricingDocument struct {
+	raw      map[string]any
+	original []byte
+}
+
+// PriceUpdate holds the prices to change; nil values are left as they are
+type PriceUpdate struct {
+	Hourly         *float64
+	Monthly        *float64
+	Yearly         *float64
+	ShutdownHourly *float64
+	StorageHourly  *float64
+}
+
+// PricingValidationError is returned when an edit would introduce errors
+type PricingValidationError struct {
+	Issues []ValidationIssue
+}
+
+func (e *PricingValidationError) Error() string {
+	messages := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		messages = append(messages, fmt.Sprintf("%s: %s", issue.Path, issue.Message))
+	}
+	return fmt.Sprintf("가격 파일 검증 실패: %s", strings.Join(messages, "; "))
+}
+
+// LoadPricingDocument reads a pricing file for editing
+func LoadPricingDocument(filename string) (*PricingDocument, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("파일 읽기 실패: %w", err)
+	}
+	return ParsePricingDocument(data)
+}
+
+// ParsePricingDocument parses pricing data for editing
+func ParsePricingDocument(data []byte) (*PricingDocument, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw map[string]any
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("JSON 파싱 실패: %w", err)
+	}
+	return &PricingDocument{raw: raw, original: data}, nil
+}
+
+// IsLegacy reports whether the document uses the legacy flavors format
+func (d *PricingDocument) IsLegacy() bool {
+	csps, _ := d.raw["csps"].(map[string]any)
+	return len(csps) == 0 && d.raw["flavors"] != nil
+}
+
+// Bytes returns the document as indented JSON
+func (d *PricingDocument) Bytes() ([]byte, error) {
+	data, err := json.MarshalIndent(d.raw, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("JSON 마샬링 실패: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Storage parses the edited document into a PricingStorage
+func (d *PricingDocument) Storage() (*PricingStorage, error) {
+	data, err := d.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	pricing := NewPricingStorage()
+	if err := pricing.Load(data); err != nil {
+		return nil, err
+	}
+	return pricing, nil
+}
+
+// Validate returns the errors the edits introduced. Errors already present
+// in the loaded file do not block saving.
+func (d *PricingDocument) Validate() ([]ValidationIssue, error) {
+	data, err := d.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool)
+	for _, issue := range ValidatePricing(d.original) {
+		existing[issue.Path+"\x00"+issue.Message] = true
+	}
+
+	var introduced []ValidationIssue
+	for _, issue := range ValidatePricing(data) {
+		if issue.Severity == SeverityError && !existing[issue.Path+"\x00"+issue.Message] {
+			introduced = append(introduced, issue)
+		}
+	}
+	return introduced, nil
+}
+
+// SaveToFile validates the document and writes it atomically
+func (d *PricingDocument) SaveToFile(filename string) error {
+	issues, err := d.Validate()
+	if err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return &PricingValidationError{Issues: issues}
+	}
+
+	data, err := d.Bytes()
+	if err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(filename), ".pricing-*.json")
+	if err != nil {
+		return fmt.Errorf("임시 파일 생성 실패: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return fmt.Errorf("파일 쓰기 실패: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("파일 쓰기 실패: %w", err)
+	}
+	if err := os.Chmod(temp.Name(), mode); err != nil {
+		return fmt.Errorf("파일 권한 설정 실패: %w", err)
+	}
+	if err := os.Rename(temp.Name(), filename); err != nil {
+		return fmt.Errorf("파일 쓰기 실패: %w", err)
+	}
+
+	d.original = data
+	return nil
+}
+
+// cspName resolves an empty CSP name to the document's default CSP
+func (d *PricingDocument) cspName(name string) string {
+	if name != "" {
+		return name
+	}
+	if defaultCSP, ok := d.raw["default_csp"].(string); ok && defaultCSP != "" {
+		return defaultCSP
+	}
+	return "nhn"
+}
+
+func (d *PricingDocument) csp(name string) (map[string]any, string, error) {
+	if d.IsLegacy() {
+		return nil, "", fmt.Errorf("기존 형식(flavors)의 가격 파일입니다. 먼저 'costcli pricing convert'로 변환하세요")
+	}
+	name = d.cspName(name)
+	csps, _ := d.raw["csps"].(map[string]any)
+	csp, ok := csps[name].(map[string]any)
+	if !ok {
+		return nil, name, fmt.Errorf("CSP %s를 찾을 수 없습니다", name)
+	}
+	return csp, name, nil
+}
+
+func (d *PricingDocument) instanceTypes(cspName string) (map[string]any, map[string]any, error) {
+	csp, _, err := d.csp(cspName)
+	if err != nil {
+		return nil, nil, err
+	}
+	types, ok := csp["instance_types"].(map[string]any)
+	if !ok {
+		types = make(map[string]any)
+		csp["instance_types"] = types
+	}
+	return csp, types, nil
+}
+
+// ResolveFlavor finds a flavor by ID or name and returns its ID
+func (d *PricingDocument) ResolveFlavor(cspName, ref string) (string, error) {
+	_, types, err := d.instanceTypes(cspName)
+	if err != nil {
+		return "", err
+	}
+	if _, exists := types[ref]; exists {
+		return ref, nil
+	}
+
+	var matches []string
+	for id, value := range types {
+		if instanceType, ok := value.(map[string]any); ok && instanceType["name"] == ref {
+			matches = append(matches, id)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("flavor %s를 찾을 수 없습니다", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		slices.Sort(matches)
+		return "", fmt.Errorf("이름이 %s인 flavor가 여러 개입니다: %s", ref, strings.Join(matches, ", "))
+	}
+}
+
+// SetPrice updates the price of a flavor in one currency (the CSP default
+// currency when empty), or its region_pricing override when region is set.
+// With effectiveFrom the current prices are kept in price_history until that
+// time.
+func (d *PricingDocument) SetPrice(cspName, flavorRef, region, currency string, update PriceUpdate, effectiveFrom string) error {
+	csp, types, err := d.instanceTypes(cspName)
+	if err != nil {
+		return err
+	}
+	flavorID, err := d.ResolveFlavor(cspName, flavorRef)
+	if err != nil {
+		return err
+	}
+	instanceType := types[flavorID].(map[string]any)
+
+	if currency == "" {
+		currency, _ = csp["default_currency"].(string)
+	}
+	if currency == "" {
+		return fmt.Errorf("통화를 지정해야 합니다")
+	}
+
+	pricing, ok := instanceType["pricing"].(map[string]any)
+	if !ok {
+		pricing = make(map[string]any)
+	}
+
+	if effectiveFrom != "" {
+		effective, err := ParseEffectiveTime(effectiveFrom)
+		if err != nil {
+			return err
+		}
+		history, _ := instanceType["price_history"].([]any)
+		for _, entry := range history {
+			version, _ := entry.(map[string]any)
+			previous, _ := version["effective_to"].(string)
+			if at, err := ParseEffectiveTime(previous); err == nil && !effective.After(at) {
+				return fmt.Errorf("적용 시점은 마지막 가격 변경(%s) 이후여야 합니다", previous)
+			}
+		}
+		version := map[string]any{
+			"effective_to": effectiveFrom,
+			"pricing":      deepCopy(pricing),
+		}
+		// 이력 항목에 리전별 가격이 없으면 공통 가격이 적용되므로 당시 값을 함께 보관
+		if regionPricing, ok := instanceType["region_pricing"]; ok {
+			version["region_pricing"] = deepCopy(regionPricing)
+		}
+		instanceType["price_history"] = append(history, version)
+	}
+
+	target := pricing
+	if region != "" {
+		regionPricing, ok := instanceType["region_pricing"].(map[string]any)
+		if !ok {
+			regionPricing = make(map[string]any)
+			instanceType["region_pricing"] = regionPricing
+		}
+		if target, ok = regionPricing[region].(map[string]any); !ok {
+			// 새 리전 가격은 공통 가격에서 시작
+			target = deepCopy(pricing).(map[string]any)
+			regionPricing[region] = target
+		}
+	}
+
+	price, ok := target[currency].(map[string]any)
+	if !ok {
+		price = make(map[string]any)
+		target[currency] = price
+	}
+	setOptional(price, "hourly", update.Hourly)
+	setOptional(price, "monthly", update.Monthly)
+	setOptional(price, "yearly", update.Yearly)
+	setOptional(price, "shutdown_hourly", update.ShutdownHourly)
+	setOptional(price, "storage_hourly", update.StorageHourly)
+	instanceType["pricing"] = pricing
+
+	return nil
+}
+
+// AddFlavor adds an instance type to a CSP
+func (d *PricingDocument) AddFlavor(cspName string, instanceType InstanceType) error {
+	_, types, err := d.instanceTypes(cspName)
+	if err != nil {
+		return err
+	}
+	if instanceType.ID == "" {
+		return fmt.Errorf("flavor ID가 필요합니다")
+	}
+	if _, exists := types[instanceType.ID]; exists {
+		return fmt.Errorf("flavor %s가 이미 있습니다", instanceType.ID)
+	}
+
+	value, err := toGeneric(instanceType)
+	if err != nil {
+		return err
+	}
+	types[instanceType.ID] = value
+	return nil
+}
+
+// RemoveFlavor removes an instance type and returns its ID
+func (d *PricingDocument) RemoveFlavor(cspName, flavorRef string) (string, error) {
+	_, types, err := d.instanceTypes(cspName)
+	if err != nil {
+		return "", err
+	}
+	flavorID, err := d.ResolveFlavor(cspName, flavorRef)
+	if err != nil {
+		return "", err
+	}
+	delete(types, flavorID)
+	return flavorID, nil
+}
+
+// AddRule appends a discount rule given as JSON to a CSP, or to the global
+// rules when global is set. Unknown rule fields are kept.
+func (d *PricingDocument) AddRule(cspName string, global bool, data []byte) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var rule map[string]any
+	if err := decoder.Decode(&rule); err != nil {
+		return "", fmt.Errorf("할인 규칙 JSON 파싱 실패: %w", err)
+	}
+
+	id, _ := rule["id"].(string)
+	if id == "" {
+		return "", fmt.Errorf("할인 규칙에 id가 필요합니다")
+	}
+	if _, location := d.findRule(id); location != "" {
+		return "", fmt.Errorf("할인 규칙 %s가 이미 있습니다 (%s)", id, location)
+	}
+	if _, exists := rule["enabled"]; !exists {
+		rule["enabled"] = true
+	}
+
+	if global {
+		if d.IsLegacy() {
+			return "", fmt.Errorf("기존 형식(flavors)의 가격 파일입니다. 먼저 'costcli pricing convert'로 변환하세요")
+		}
+		rules, _ := d.raw["global_discount_rules"].([]any)
+		d.raw["global_discount_rules"] = append(rules, rule)
+		return "global_discount_rules", nil
+	}
+
+	csp, name, err := d.csp(cspName)
+	if err != nil {
+		return "", err
+	}
+	rules, _ := csp["discount_rules"].([]any)
+	csp["discount_rules"] = append(rules, rule)
+	return "csps." + name + ".discount_rules", nil
+}
+
+// SetRuleEnabled enables or disables a discount rule by ID, searching the
+// global rules and every CSP, and returns where the rule was found
+func (d *PricingDocument) SetRuleEnabled(id string, enabled bool) (string, error) {
+	rule, location := d.findRule(id)
+	if rule == nil {
+		return "", fmt.Errorf("할인 규칙 %s를 찾을 수 없습니다", id)
+	}
+	rule["enabled"] = enabled
+	return location, nil
+}
+
+func (d *PricingDocument) findRule(id string) (map[string]any, string) {
+	find := func(rules []any) map[string]any {
+		for _, value := range rules {
+			if rule, ok := value.(map[string]any); ok && rule["id"] == id {
+				return rule
+			}
+		}
+		return nil
+	}
+
+	if rules, ok := d.raw["global_discount_rules"].([]any); ok {
+		if rule := find(rules); rule != nil {
+			return rule, "global_discount_rules"
+		}
+	}
+	csps, _ := d.raw["csps"].(map[string]any)
+	for _, name := range sortedKeys(csps) {
+		csp, _ := csps[name].(map[string]any)
+		if rules, ok := csp["discount_rules"].([]any); ok {
+			if rule := find(rules); rule != nil {
+				return rule, "csps." + name + ".discount_rules"
+			}
+		}
+	}
+	return nil, ""
+}
+
+// ConvertLegacy rewrites a legacy flavors document into the v2 schema under
+// one CSP. Legacy stopped-instance billing (10% for 90 days) is kept as the
+// CSP's shutdown_billing; unknown flavor fields are carried over. All flavors
+// must be priced in one currency, which becomes the CSP's default currency.
+func (d *PricingDocument) ConvertLegacy(cspName string, regions []string) error {
+	if !d.IsLegacy() {
+		return fmt.Errorf("이미 새 형식(csps)의 가격 파일입니다")
+	}
+	if cspName == "" {
+		cspName = "nhn"
+	}
+
+	flavors, _ := d.raw["flavors"].(map[string]any)
+	legacyFields := map[string]string{
+		"hourly_price":          "hourly",
+		"monthly_price":         "monthly",
+		"yearly_price":          "yearly",
+		"shutdown_hourly_price": "shutdown_hourly",
+		"storage_hourly_price":  "storage_hourly",
+	}
+
+	currencyFlavors := make(map[string][]string)
+	types := make(map[string]any, len(flavors))
+	for _, flavorID := range sortedKeys(flavors) {
+		flavor, ok := flavors[flavorID].(map[string]any)
+		if !ok {
+			return fmt.Errorf("flavor %s의 형식이 올바르지 않습니다", flavorID)
+		}
+
+		currency, _ := flavor["currency"].(string)
+		if currency == "" {
+			currency = "KRW"
+		}
+		currencyFlavors[currency] = append(currencyFlavors[currency], flavorID)
+
+		price := make(map[string]any)
+		instanceType := map[string]any{
+			"id":      flavorID,
+			"name":    flavorID,
+			"pricing": map[string]any{currency: price},
+		}
+		for key, value := range flavor {
+			switch key {
+			case "flavor_id", "currency":
+			default:
+				if field, exists := legacyFields[key]; exists {
+					price[field] = value
+				} else {
+					instanceType[key] = value
+				}
+			}
+		}
+		if len(regions) > 0 {
+			availability := make([]any, len(regions))
+			for i, region := range regions {
+				availability[i] = region
+			}
+			instanceType["availability"] = availability
+		}
+		types[flavorID] = instanceType
+	}
+
+	defaultCurrency, most := "KRW", 0
+	for _, currency := range sortedKeys(currencyFlavors) {
+		if len(currencyFlavors[currency]) > most {
+			defaultCurrency, most = currency, len(currencyFlavors[currency])
+		}
+	}
+
+	// 새 형식은 모든 flavor에 기본 통화 가격이 필요하므로 환율 없이는 변환할 수 없음
+	if len(currencyFlavors) > 1 {
+		var groups, others []string
+		for _, currency := range sortedKeys(currencyFlavors) {
+			groups = append(groups, fmt.Sprintf("%s: %s", currency, strings.Join(currencyFlavors[currency], ", ")))
+			if currency != defaultCurrency {
+				others = append(others, currency)
+			}
+		}
+		return fmt.Errorf("여러 통화의 가격이 섞여 있어 변환할 수 없습니다 (%s). 새 형식은 모든 flavor에 기본 통화(%s) 가격이 필요하므로, 환율을 적용해 %s 가격을 %s로 바꾼 뒤 다시 변환하세요",
+			strings.Join(groups, "; "), defaultCurrency, strings.Join(others, ", "), defaultCurrency)
+	}
+
+	regionList := make([]any, len(regions))
+	for i, region := range regions {
+		regionList[i] = map[string]any{"code": region, "name": region}
+	}
+
+	delete(d.raw, "flavors")
+	d.raw["version"] = "2.0.0"
+	d.raw["default_csp"] = cspName
+	d.raw["csps"] = map[string]any{
+		cspName: map[string]any{
+			"name":             cspName,
+			"default_currency": defaultCurrency,
+			"regions":          regionList,
+			"instance_types":   types,
+			"discount_rules":   []any{},
+			"shutdown_billing": map[string]any{
+				"rate_percent":  10,
+				"eligible_days": defaultShutdownEligibleDays,
+			},
+		},
+	}
+	return nil
+}
+
+func setOptional(object map[string]any, key string, value *float64) {
+	if value != nil {
+		object[key] = *value
+	}
+}
+
+// toGeneric converts a typed value into the generic JSON tree form
+func toGeneric(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("JSON 마샬링 실패: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("JSON 파싱 실패: %w", err)
+	}
+	return generic, nil
+}
+
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for key, item := range v {
+			copied[key] = deepCopy(item)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FlavorSpec is a flavor as synced from Nova by cost-collect
+type FlavorSpec struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	VCPU        int    `json:"vcpu"`
+	MemoryMB    int    `json:"memory_mb"`
+	DiskGB      int    `json:"disk_gb"`
+	EphemeralGB int    `json:"ephemeral_gb,omitempty"`
+	Public      bool   `json:"public"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+// RegionFlavors holds the flavors of one region as of the last sync
+type RegionFlavors struct {
+	LastSync time.Time              `json:"last_sync"`
+	Flavors  map[string]*FlavorSpec `json:"flavors"`
+}
+
+// FlavorStorage is the flavor catalog written by `cost-collect flavors sync`
+type FlavorStorage struct {
+	Regions map[string]*RegionFlavors `json:"regions"`
+}
+
+// DefaultFlavorRegion is the region key used when no region is configured
+const DefaultFlavorRegion = "default"
+
+func NewFlavorStorage() *FlavorStorage {
+	return &FlavorStorage{
+		Regions: make(map[string]*RegionFlavors),
+	}
+}
+
+// FlavorFilePath returns the flavor catalog stored next to the instance file
+func FlavorFilePath(instanceFile string) string {
+	return filepath.Join(filepath.Dir(instanceFile), "flavors.json")
+}
+
+func (s *FlavorStorage) LoadFromFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil // 동기화하지 않았으면 빈 목록으로 시작
+		}
+		return fmt.Errorf("파일 읽기 실패: %w", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return fmt.Errorf("JSON 파싱 실패: %w", err)
+	}
+	if s.Regions == nil {
+		s.Regions = make(map[string]*RegionFlavors)
+	}
+
+	return nil
+}
+
+// GetFlavor looks a flavor up in the given region, then in any region
+func (s *FlavorStorage) GetFlavor(region, flavorID string) (*FlavorSpec, bool) {
+	if region == "" {
+		region = DefaultFlavorRegion
+	}
+	if flavors, exists := s.Regions[region]; exists {
+		if flavor, exists := flavors.Flavors[flavorID]; exists {
+			return flavor, true
+		}
+	}
+	for _, flavors := range s.Regions {
+		if flavor, exists := flavors.Flavors[flavorID]; exists {
+			return flavor, true
+		}
+	}
+	return nil, false
+}
+
+// GetFallbackPrice estimates the price of a flavor missing from the pricing
+// file from the CSP's per-vCPU and per-GB-memory rates.
+func (p *PricingStorage) GetFallbackPrice(cspName, flavorID string, spec *FlavorSpec) (*FlavorPrice, bool) {
+	if p.NewPricingSchema == nil || spec == nil {
+		return nil, false
+	}
+	csp, exists := p.CSPs[p.cspOrDefault(cspName)]
+	if !exists || csp.FallbackPricing == nil {
+		return nil, false
+	}
+
+	hourly := csp.FallbackPricing.VCPUHourly*float64(spec.VCPU) +
+		csp.FallbackPricing.MemoryGBHourly*float64(spec.MemoryMB)/1024
+	price := &FlavorPrice{
+		FlavorID:    flavorID,
+		HourlyPrice: hourly,
+		Currency:    csp.DefaultCurrency,
+	}
+	if csp.StorageGBHourly > 0 && spec.DiskGB > 0 {
+		storageHourly := csp.StorageGBHourly * float64(spec.DiskGB)
+		price.StorageHourlyPrice = &storageHourly
+	}
+	return price, true
+}
+
+// GetFlavorSpec returns the specs the pricing file records for a flavor,
+// even when it has no price in the default currency
+func (p *PricingStorage) GetFlavorSpec(cspName, flavorID string) (*FlavorSpec, bool) {
+	instanceType, exists := p.GetInstanceTypeInfo(p.cspOrDefault(cspName), flavorID)
+	if !exists || instanceType.VCPU == 0 {
+		return nil, false
+	}
+	return &FlavorSpec{
+		ID:       flavorID,
+		Name:     instanceType.Name,
+		VCPU:     instanceType.VCPU,
+		MemoryMB: instanceType.MemoryMB,
+		DiskGB:   instanceType.DiskGB,
+	}, true
+}
@@ -4,15 +4,35 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 )
 
 // Legacy pricing structure for backward compatibility
 type FlavorPrice struct {
-	FlavorID    string  `json:"flavor_id"`
-	HourlyPrice float64 `json:"hourly_price"`
-	Currency    string  `json:"currency"`
+	FlavorID     string  `json:"flavor_id"`
+	HourlyPrice  float64 `json:"hourly_price"`
+	MonthlyPrice float64 `json:"monthly_price,omitempty"`
+	YearlyPrice  float64 `json:"yearly_price,omitempty"`
+	Currency     string  `json:"currency"`
+
+	// 정지 상태 시간당 요금 (설정하지 않으면 CSP의 shutdown_billing 비율 적용)
+	ShutdownHourlyPrice *float64 `json:"shutdown_hourly_price,omitempty"`
+	// storage_only 상태 시간당 요금 (설정하지 않으면 과금하지 않음)
+	StorageHourlyPrice *float64 `json:"storage_hourly_price,omitempty"`
 }
 
+// Billing models
+const (
+	BillingModelHourly     = "hourly"      // 실행 시간 × 시간당 요금 (기본값)
+	BillingModelMonthlyCap = "monthly_cap" // 청구 월마다 시간당 요금 합계를 월 요금으로 제한
+)
+
+// Contract types
+const (
+	ContractMonthly = "monthly" // 월 정액을 기간에 비례 배분
+	ContractYearly  = "yearly"  // 연 정액을 기간에 비례 배분
+)
+
 type LegacyPricingStorage struct {
 	Flavors map[string]*FlavorPrice `json:"flavors"`
 }
@@ -27,13 +47,20 @@ type InstanceType struct {
 	DiskGB            int                `json:"disk_gb"`
 	NetworkPerformance string             `json:"network_performance"`
 	Pricing           map[string]Pricing `json:"pricing"`
+	// 리전별 가격 (해당 리전에서 pricing 대신 적용)
+	RegionPricing     map[string]map[string]Pricing `json:"region_pricing,omitempty"`
 	Availability      []string           `json:"availability"`
+	Contract          string             `json:"contract,omitempty"`
+	// 이전 가격 이력 (가격 변경 전 사용분은 당시 가격으로 계산)
+	PriceHistory []PriceVersion `json:"price_history,omitempty"`
 }
 
 type Pricing struct {
-	Hourly  float64 `json:"hourly"`
-	Monthly float64 `json:"monthly"`
-	Yearly  float64 `json:"yearly"`
+	Hourly         float64  `json:"hourly"`
+	Monthly        float64  `json:"monthly"`
+	Yearly         float64  `json:"yearly"`
+	ShutdownHourly *float64 `json:"shutdown_hourly,omitempty"`
+	StorageHourly  *float64 `json:"storage_hourly,omitempty"`
 }
 
 type Region struct {
@@ -42,10 +69,54 @@ type Region struct {
 	DisplayName string `json:"display_name"`
 }
 
+// DiscountCondition is either a leaf comparison (type/operator/value) or a
+// group combining nested conditions with all/any/not.
 type DiscountCondition struct {
-	Type     string      `json:"type"`
-	Operator string      `json:"operator"`
-	Value    any         `json:"value"`
+	Type     string      `json:"type,omitempty"`
+	Operator string      `json:"operator,omitempty"`
+	Value    any         `json:"value,omitempty"`
+	Key      string      `json:"key,omitempty"`
+
+	All []DiscountCondition `json:"all,omitempty"`
+	Any []DiscountCondition `json:"any,omitempty"`
+	Not *DiscountCondition  `json:"not,omitempty"`
+}
+
+// IsGroup reports whether the condition combines nested conditions
+func (c *DiscountCondition) IsGroup() bool {
+	return c.All != nil || c.Any != nil || c.Not != nil
+}
+
+// Condition value kinds
+const (
+	ConditionKindNumber = "number"
+	ConditionKindString = "string"
+)
+
+// ConditionTypes lists the supported leaf condition types and the kind of
+// value they compare.
+var ConditionTypes = map[string]string{
+	"instance_age_days": ConditionKindNumber,
+	"running_hours":     ConditionKindNumber,
+	"running_minutes":   ConditionKindNumber,
+	"monthly_hours":     ConditionKindNumber,
+	"shutdown_age_days": ConditionKindNumber,
+	"vcpu":              ConditionKindNumber,
+	"memory_mb":         ConditionKindNumber,
+	"instance_status":   ConditionKindString,
+	"flavor_id":         ConditionKindString,
+	"flavor_name":       ConditionKindString,
+	"region":            ConditionKindString,
+	"csp":               ConditionKindString,
+	"instance_name":     ConditionKindString,
+	"tag":               ConditionKindString,
+	"metadata":          ConditionKindString,
+}
+
+// ConditionOperators lists the operators allowed for each value kind
+var ConditionOperators = map[string][]string{
+	ConditionKindNumber: {"<=", ">=", "<", ">", "==", "!=", "in", "not_in"},
+	ConditionKindString: {"==", "!=", "in", "not_in", "matches"},
 }
 
 type DiscountRule struct {
@@ -58,6 +129,75 @@ type DiscountRule struct {
 	EffectiveFrom   string              `json:"effective_from"`
 	EffectiveTo     *string             `json:"effective_to"`
 	Enabled         bool                `json:"enabled"`
+
+	// 할인 중첩 규칙
+	Priority          int     `json:"priority,omitempty"`
+	StackMode         string  `json:"stack_mode,omitempty"`
+	ExclusiveGroup    string  `json:"exclusive_group,omitempty"`
+	MaxDiscountAmount float64 `json:"max_discount_amount,omitempty"`
+
+	// 할인 계산 방식
+	Method         string         `json:"method,omitempty"`
+	Scope          string         `json:"scope,omitempty"`
+	DiscountAmount float64        `json:"discount_amount,omitempty"`
+	AmountPer      string         `json:"amount_per,omitempty"`
+	Tiers          []DiscountTier `json:"tiers,omitempty"`
+}
+
+// DiscountTier discounts running hours up to UpToHours (cumulative) by
+// DiscountPercent. A nil UpToHours covers all remaining hours.
+type DiscountTier struct {
+	UpToHours       *float64 `json:"up_to_hours"`
+	DiscountPercent float64  `json:"discount_percent"`
+}
+
+// Discount methods
+const (
+	DiscountMethodPercent = "percent" // 비용의 일정 비율 할인 (기본값)
+	DiscountMethodFixed   = "fixed"   // 고정 금액 할인 (크레딧)
+	DiscountMethodTiered  = "tiered"  // 사용 시간 구간별 할인율
+)
+
+// Discount scopes
+const (
+	DiscountScopeInstance = "instance" // 인스턴스별 적용 (기본값)
+	DiscountScopeSummary  = "summary"  // 조건에 맞는 인스턴스 합계에 적용
+)
+
+// Fixed amount periods
+const (
+	AmountPerPeriod = "period" // 계산 기간마다 한 번 (기본값)
+	AmountPerMonth  = "month"  // 월 금액을 계산 기간에 비례 배분
+)
+
+// Discount stack modes
+const (
+	StackModeAdditive = "additive" // 기본 비용 기준으로 할인액 산정
+	StackModeCompound = "compound" // 앞선 할인 적용 후 남은 비용 기준으로 할인액 산정
+)
+
+// EffectiveWindow parses EffectiveFrom/EffectiveTo. A zero time means the
+// rule is unbounded on that side.
+func (r *DiscountRule) EffectiveWindow() (time.Time, time.Time, error) {
+	var from, to time.Time
+
+	if r.EffectiveFrom != "" {
+		parsed, err := time.Parse(time.RFC3339, r.EffectiveFrom)
+		if err != nil {
+			return from, to, fmt.Errorf("effective_from 형식 오류 (%s): %w", r.EffectiveFrom, err)
+		}
+		from = parsed
+	}
+
+	if r.EffectiveTo != nil && *r.EffectiveTo != "" {
+		parsed, err := time.Parse(time.RFC3339, *r.EffectiveTo)
+		if err != nil {
+			return from, to, fmt.Errorf("effective_to 형식 오류 (%s): %w", *r.EffectiveTo, err)
+		}
+		to = parsed
+	}
+
+	return from, to, nil
 }
 
 type CSPProvider struct {
@@ -68,18 +208,67 @@ type CSPProvider struct {
 	Regions         []Region                 `json:"regions"`
 	InstanceTypes   map[string]InstanceType  `json:"instance_types"`
 	DiscountRules   []DiscountRule           `json:"discount_rules"`
+	BillingModel    string                   `json:"billing_model,omitempty"`
+	Contract        string                   `json:"contract,omitempty"`
+	BillingRules    BillingRules             `json:"billing_rules,omitzero"`
+	ShutdownBilling *ShutdownBilling         `json:"shutdown_billing,omitempty"`
+	// 상태별 과금 구분 (full, reduced, storage_only, free). 없는 상태는 기본값 사용
+	StatusBilling map[string]string `json:"status_billing,omitempty"`
+	// storage_only 상태의 GB당 시간 요금 (default_currency 기준, flavor의 storage_hourly가 우선)
+	StorageGBHourly float64 `json:"storage_gb_hourly,omitempty"`
+	// 가격이 없는 flavor를 사양으로 추정할 때의 단가 (default_currency 기준)
+	FallbackPricing *FallbackPricing `json:"fallback_pricing,omitempty"`
+}
+
+// FallbackPricing estimates the hourly price of an unpriced flavor from its
+// vCPU count and memory size
+type FallbackPricing struct {
+	VCPUHourly     float64 `json:"vcpu_hourly"`
+	MemoryGBHourly float64 `json:"memory_gb_hourly"`
 }
 
+// BillingRules describes how the CSP meters usage and rounds charges
+type BillingRules struct {
+	// 과금 단위 (second, minute, hour). 연속된 상태 구간마다 단위로 올림됩니다.
+	Granularity string `json:"granularity,omitempty"`
+	// 인스턴스 시작마다 적용되는 최소 과금 시간 (초). 연속된 실행 구간마다 한 번 적용됩니다.
+	MinimumBillableSeconds int `json:"minimum_billable_seconds,omitempty"`
+	// 통화별 금액 반올림 규칙
+	Rounding map[string]RoundingRule `json:"rounding,omitempty"`
+}
+
+// RoundingRule rounds amounts to Decimals places using Mode, one of the
+// money rounding modes (half_up, up, down). KRW typically uses 0 decimals,
+// USD 2; negative decimals round to tens, hundreds, ...
+type RoundingRule struct {
+	Decimals int    `json:"decimals"`
+	Mode     string `json:"mode,omitempty"`
+}
+
+// Billing granularities
+const (
+	GranularitySecond = "second"
+	GranularityMinute = "minute"
+	GranularityHour   = "hour"
+)
+
 type CurrencyConversion struct {
 	Rates       map[string]float64 `json:"rates"`
 	LastUpdated string             `json:"last_updated"`
 }
 
+// DiscountPolicy controls how multiple matching discount rules are combined
+type DiscountPolicy struct {
+	DefaultStackMode        string   `json:"default_stack_mode,omitempty"`
+	MaxTotalDiscountPercent *float64 `json:"max_total_discount_percent,omitempty"`
+}
+
 type GlobalSettings struct {
 	CacheTTLSeconds     int                 `json:"cache_ttl_seconds"`
 	DefaultDuration     string              `json:"default_duration"`
 	SupportedFormats    []string            `json:"supported_formats"`
 	CurrencyConversion  CurrencyConversion  `json:"currency_conversion"`
+	DiscountPolicy      DiscountPolicy      `json:"discount_policy"`
 }
 
 type NewPricingSchema struct {
@@ -111,7 +300,11 @@ func (p *PricingStorage) LoadFromFile(filename string) error {
 	if err != nil {
 		return fmt.Errorf("파일 읽기 실패: %w", err)
 	}
+	return p.Load(data)
+}
 
+// Load parses pricing data in either the legacy or the new format
+func (p *PricingStorage) Load(data []byte) error {
 	// Try to determine format by checking for new schema structure
 	var structureCheck struct {
 		Version string             `json:"version"`
@@ -147,6 +340,14 @@ func (p *PricingStorage) LoadFromFile(filename string) error {
 
 // GetFlavorPrice supports both legacy and new formats
 func (p *PricingStorage) GetFlavorPrice(flavorID string) (*FlavorPrice, bool) {
+	return p.GetFlavorPriceAt("", "", flavorID, time.Time{})
+}
+
+// GetFlavorPriceAt returns the flavor price of a CSP (the default CSP when
+// empty) in effect at the given time in the given region. A zero time returns
+// the current price, and an empty region or one without an override the
+// common price.
+func (p *PricingStorage) GetFlavorPriceAt(cspName, region, flavorID string, at time.Time) (*FlavorPrice, bool) {
 	// Check legacy format first
 	if len(p.Flavors) > 0 {
 		price, exists := p.Flavors[flavorID]
@@ -155,19 +356,18 @@ func (p *PricingStorage) GetFlavorPrice(flavorID string) (*FlavorPrice, bool) {
 	
 	// Check new format
 	if p.NewPricingSchema != nil {
-		defaultCSP := p.DefaultCSP
-		if defaultCSP == "" {
-			defaultCSP = "nhn" // fallback
-		}
-		
-		if csp, exists := p.CSPs[defaultCSP]; exists {
+		if csp, exists := p.CSPs[p.cspOrDefault(cspName)]; exists {
 			if instanceType, exists := csp.InstanceTypes[flavorID]; exists {
 				currency := csp.DefaultCurrency
-				if pricing, exists := instanceType.Pricing[currency]; exists {
+				if pricing, exists := instanceType.RegionalPricingAt(region, at)[currency]; exists {
 					return &FlavorPrice{
-						FlavorID:    flavorID,
-						HourlyPrice: pricing.Hourly,
-						Currency:    currency,
+						FlavorID:            flavorID,
+						HourlyPrice:         pricing.Hourly,
+						MonthlyPrice:        pricing.Monthly,
+						YearlyPrice:         pricing.Yearly,
+						Currency:            currency,
+						ShutdownHourlyPrice: pricing.ShutdownHourly,
+						StorageHourlyPrice:  storageHourly(csp, instanceType, pricing, currency),
 					}, true
 				}
 			}
@@ -177,6 +377,84 @@ func (p *PricingStorage) GetFlavorPrice(flavorID string) (*FlavorPrice, bool) {
 	return nil, false
 }
 
+// GetFlavorPriceInCurrencyAt returns the native price of a flavor in the
+// given currency when the new format lists one under InstanceType.Pricing,
+// for the price of a CSP in effect at the given time in the given region
+func (p *PricingStorage) GetFlavorPriceInCurrencyAt(cspName, region, flavorID, currency string, at time.Time) (*FlavorPrice, bool) {
+	if p.NewPricingSchema == nil {
+		if price, exists := p.Flavors[flavorID]; exists && price.Currency == currency {
+			return price, true
+		}
+		return nil, false
+	}
+
+	if csp, exists := p.CSPs[p.cspOrDefault(cspName)]; exists {
+		if instanceType, exists := csp.InstanceTypes[flavorID]; exists {
+			if pricing, exists := instanceType.RegionalPricingAt(region, at)[currency]; exists {
+				return &FlavorPrice{
+					FlavorID:            flavorID,
+					HourlyPrice:         pricing.Hourly,
+					MonthlyPrice:        pricing.Monthly,
+					YearlyPrice:         pricing.Yearly,
+					Currency:            currency,
+					ShutdownHourlyPrice: pricing.ShutdownHourly,
+					StorageHourlyPrice:  storageHourly(csp, instanceType, pricing, currency),
+				}, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// storageHourly returns the storage-only hourly price of a flavor: the
+// flavor's own storage_hourly, else the CSP per-GB rate times the flavor disk
+// when the price is in the CSP default currency.
+func storageHourly(csp CSPProvider, instanceType InstanceType, pricing Pricing, currency string) *float64 {
+	if pricing.StorageHourly != nil {
+		return pricing.StorageHourly
+	}
+	if csp.StorageGBHourly <= 0 || instanceType.DiskGB <= 0 || currency != csp.DefaultCurrency {
+		return nil
+	}
+	hourly := csp.StorageGBHourly * float64(instanceType.DiskGB)
+	return &hourly
+}
+
+// GetBillingTerms returns the billing model and contract type that apply to
+// a flavor. A flavor-level contract overrides the CSP (project) contract.
+func (p *PricingStorage) GetBillingTerms(cspName, flavorID string) (string, string) {
+	billingModel := BillingModelHourly
+	if p.NewPricingSchema == nil {
+		return billingModel, ""
+	}
+
+	csp, exists := p.CSPs[cspName]
+	if !exists {
+		return billingModel, ""
+	}
+	if csp.BillingModel != "" {
+		billingModel = csp.BillingModel
+	}
+
+	contract := csp.Contract
+	if instanceType, exists := csp.InstanceTypes[flavorID]; exists && instanceType.Contract != "" {
+		contract = instanceType.Contract
+	}
+
+	return billingModel, contract
+}
+
+// IsFlavorAvailable reports whether a CSP lists the flavor as available in
+// the region. Flavors without availability data are assumed available.
+func (p *PricingStorage) IsFlavorAvailable(cspName, flavorID, region string) bool {
+	instanceType, exists := p.GetInstanceTypeInfo(p.cspOrDefault(cspName), flavorID)
+	if !exists {
+		return true
+	}
+	return instanceType.AvailableIn(region)
+}
+
 // GetInstanceTypeInfo returns detailed instance information from new format
 func (p *PricingStorage) GetInstanceTypeInfo(cspName, instanceTypeID string) (*InstanceType, bool) {
 	if p.NewPricingSchema == nil {
@@ -192,21 +470,42 @@ func (p *PricingStorage) GetInstanceTypeInfo(cspName, instanceTypeID string) (*I
 	return nil, false
 }
 
+// DefaultCSPName returns the default CSP, falling back to nhn
+func (p *PricingStorage) DefaultCSPName() string {
+	if p.NewPricingSchema == nil || p.DefaultCSP == "" {
+		return "nhn"
+	}
+	return p.DefaultCSP
+}
+
+// cspOrDefault returns the CSP name, or the default CSP when it is empty
+func (p *PricingStorage) cspOrDefault(cspName string) string {
+	if cspName == "" {
+		return p.DefaultCSPName()
+	}
+	return cspName
+}
+
+// CSPNames returns the CSPs in the pricing file, sorted. The legacy format
+// only has the default CSP.
+func (p *PricingStorage) CSPNames() []string {
+	if p.NewPricingSchema == nil {
+		return []string{p.DefaultCSPName()}
+	}
+	return sortedKeys(p.CSPs)
+}
+
 // IsNewFormat returns true if using new pricing schema
 func (p *PricingStorage) IsNewFormat() bool {
 	return p.NewPricingSchema != nil
 }
 
-// GetFlavorName returns human-readable flavor name
-func (p *PricingStorage) GetFlavorName(flavorID string) string {
+// GetFlavorName returns human-readable flavor name of a CSP's flavor (the
+// default CSP when empty)
+func (p *PricingStorage) GetFlavorName(cspName, flavorID string) string {
 	// Check new format first for detailed names
 	if p.NewPricingSchema != nil {
-		defaultCSP := p.DefaultCSP
-		if defaultCSP == "" {
-			defaultCSP = "nhn"
-		}
-		
-		if csp, exists := p.CSPs[defaultCSP]; exists {
+		if csp, exists := p.CSPs[p.cspOrDefault(cspName)]; exists {
 			if instanceType, exists := csp.InstanceTypes[flavorID]; exists {
 				return instanceType.Name
 			}
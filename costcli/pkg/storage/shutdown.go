@@ -0,0 +1,66 @@
+package storage
+
+// DiscountTypeShutdown marks the old "shutdown discount" rules. They are no
+// longer applied as discounts; their percent is turned into a shutdown rate.
+const DiscountTypeShutdown = "shutdown"
+
+// defaultShutdownEligibleDays is NHN Cloud's reduced-rate window for stopped
+// instances, counted from instance creation.
+const defaultShutdownEligibleDays = 90
+
+// ShutdownBilling describes how hours spent stopped (SHUTOFF) are charged.
+// Without it stopped hours are free.
+type ShutdownBilling struct {
+	// 정지 시간당 요금 = 시간당 요금 × rate_percent / 100 (flavor의 shutdown_hourly가 우선)
+	RatePercent float64 `json:"rate_percent"`
+	// 생성 후 이 기간 안의 정지 시간에만 감면 요금 적용 (0이면 제한 없음)
+	EligibleDays int `json:"eligible_days,omitempty"`
+	// 감면 기간이 지난 정지 시간의 요금 비율 (기본값 100)
+	AfterWindowPercent *float64 `json:"after_window_percent,omitempty"`
+}
+
+// AfterWindowRatePercent returns the rate applied to stopped hours after the
+// eligibility window.
+func (s ShutdownBilling) AfterWindowRatePercent() float64 {
+	if s.AfterWindowPercent == nil {
+		return 100
+	}
+	return *s.AfterWindowPercent
+}
+
+// GetShutdownBilling returns how stopped hours are charged for a CSP.
+//
+// The legacy format follows NHN Cloud (10% of the hourly price for 90 days
+// after creation). In the new format csps.<csp>.shutdown_billing is used, or
+// is derived from an enabled "shutdown" discount rule so older pricing files
+// keep their intent.
+func (p *PricingStorage) GetShutdownBilling(cspName string) (ShutdownBilling, bool) {
+	if p.NewPricingSchema == nil {
+		return ShutdownBilling{RatePercent: 10, EligibleDays: defaultShutdownEligibleDays}, true
+	}
+
+	csp, exists := p.CSPs[cspName]
+	if !exists {
+		return ShutdownBilling{}, false
+	}
+	if csp.ShutdownBilling != nil {
+		return *csp.ShutdownBilling, true
+	}
+
+	for _, rule := range csp.DiscountRules {
+		if !rule.Enabled || rule.Type != DiscountTypeShutdown {
+			continue
+		}
+
+		billing := ShutdownBilling{RatePercent: 100 - rule.DiscountPercent, EligibleDays: defaultShutdownEligibleDays}
+		for _, condition := range rule.Conditions {
+			days, ok := condition.Value.(float64)
+			if ok && condition.Operator == "<=" && (condition.Type == "instance_age_days" || condition.Type == "shutdown_age_days") {
+				billing.EligibleDays = int(days)
+			}
+		}
+		return billing, true
+	}
+
+	return ShutdownBilling{}, false
+}
@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"strings"
+	"time"
+)
+
+// OpenStack server statuses (Nova server.status)
+const (
+	StatusActive           = "ACTIVE"
+	StatusBuild            = "BUILD"
+	StatusShutoff          = "SHUTOFF"
+	StatusPaused           = "PAUSED"
+	StatusSuspended        = "SUSPENDED"
+	StatusShelved          = "SHELVED"
+	StatusShelvedOffloaded = "SHELVED_OFFLOADED"
+	StatusRescue           = "RESCUE"
+	StatusError            = "ERROR"
+	StatusResize           = "RESIZE"
+	StatusVerifyResize     = "VERIFY_RESIZE"
+	StatusRevertResize     = "REVERT_RESIZE"
+	StatusReboot           = "REBOOT"
+	StatusHardReboot       = "HARD_REBOOT"
+	StatusMigrating        = "MIGRATING"
+	StatusRebuild          = "REBUILD"
+	StatusPassword         = "PASSWORD"
+	StatusSoftDeleted      = "SOFT_DELETED"
+	StatusDeleted          = "DELETED"
+	StatusUnknown          = "UNKNOWN"
+)
+
+// Nova power states (OS-EXT-STS:power_state)
+const (
+	PowerStateNoState   = 0
+	PowerStateRunning   = 1
+	PowerStatePaused    = 3
+	PowerStateShutdown  = 4
+	PowerStateCrashed   = 6
+	PowerStateSuspended = 7
+)
+
+// Billing classes describe how time spent in a status is charged
+const (
+	BillingClassFull        = "full"         // 시간당 요금 전액
+	BillingClassReduced     = "reduced"      // 정지 요금 (shutdown_billing)
+	BillingClassStorageOnly = "storage_only" // 디스크 보관 요금만
+	BillingClassFree        = "free"         // 과금하지 않음
+)
+
+// DefaultStatusBilling is the billing class of each status unless a CSP
+// overrides it in status_billing. Unlisted statuses are billed as reduced,
+// like any non-running instance used to be.
+var DefaultStatusBilling = map[string]string{
+	StatusActive:           BillingClassFull,
+	StatusPaused:           BillingClassFull,
+	StatusRescue:           BillingClassFull,
+	StatusResize:           BillingClassFull,
+	StatusVerifyResize:     BillingClassFull,
+	StatusRevertResize:     BillingClassFull,
+	StatusReboot:           BillingClassFull,
+	StatusHardReboot:       BillingClassFull,
+	StatusMigrating:        BillingClassFull,
+	StatusRebuild:          BillingClassFull,
+	StatusPassword:         BillingClassFull,
+	StatusShutoff:          BillingClassReduced,
+	StatusSuspended:        BillingClassReduced,
+	StatusShelved:          BillingClassStorageOnly,
+	StatusShelvedOffloaded: BillingClassStorageOnly,
+	StatusBuild:            BillingClassFree,
+	StatusError:            BillingClassFree,
+	StatusSoftDeleted:      BillingClassFree,
+	StatusDeleted:          BillingClassFree,
+}
+
+// BillingClasses lists the valid billing classes
+var BillingClasses = []string{BillingClassFull, BillingClassReduced, BillingClassStorageOnly, BillingClassFree}
+
+// EffectiveStatus combines a Nova status with its power state. An ACTIVE
+// server whose guest is shut down, paused or suspended is reported as that
+// state; a missing power state on an ACTIVE server is treated as running.
+func EffectiveStatus(status string, powerState int) string {
+	status = strings.ToUpper(strings.TrimSpace(status))
+
+	switch status {
+	case StatusActive:
+		switch powerState {
+		case PowerStateShutdown, PowerStateCrashed:
+			return StatusShutoff
+		case PowerStatePaused:
+			return StatusPaused
+		case PowerStateSuspended:
+			return StatusSuspended
+		}
+		return StatusActive
+
+	case "":
+		switch powerState {
+		case PowerStateRunning:
+			return StatusActive
+		case PowerStateShutdown:
+			return StatusShutoff
+		}
+		return StatusUnknown
+	}
+
+	return status
+}
+
+// IsRunningStatus reports whether the guest is up and running
+func IsRunningStatus(status string, powerState int) bool {
+	return EffectiveStatus(status, powerState) == StatusActive
+}
+
+// GetBillingClass returns how time in a status is charged for a CSP. The
+// CSP's status_billing overrides DefaultStatusBilling.
+func (p *PricingStorage) GetBillingClass(cspName, status string, powerState int) string {
+	effective := EffectiveStatus(status, powerState)
+
+	if p.NewPricingSchema != nil {
+		if csp, exists := p.CSPs[cspName]; exists {
+			if class, exists := csp.StatusBilling[effective]; exists {
+				return class
+			}
+		}
+	}
+
+	if class, exists := DefaultStatusBilling[effective]; exists {
+		return class
+	}
+	return BillingClassReduced
+}
+
+// StatusPeriod is a continuous period an instance spent in one recorded state
+type StatusPeriod struct {
+	Status     string
+	PowerState int
+	Start      time.Time
+	End        time.Time
+}
+
+// StatusPeriods returns the recorded states of an instance in time order.
+// state_history is preferred over status_history; the time between creation
+// and the first state_history record is attributed to the current state.
+// Without any history the current state covers the whole lifetime. Periods
+// never extend past the deletion time.
+func (i *InstanceState) StatusPeriods() []StatusPeriod {
+	var periods []StatusPeriod
+	observedUntil := i.ObservedUntil()
+
+	switch {
+	case len(i.StateHistory) > 0:
+		if first := i.StateHistory[0]; first.Timestamp.After(i.CreatedAt) {
+			periods = append(periods, StatusPeriod{Status: i.CurrentStatus, PowerState: i.CurrentPowerState, Start: i.CreatedAt, End: first.Timestamp})
+		}
+		for j, record := range i.StateHistory {
+			end := observedUntil
+			if j+1 < len(i.StateHistory) {
+				end = i.StateHistory[j+1].Timestamp
+			}
+			periods = append(periods, StatusPeriod{Status: record.Status, PowerState: record.PowerState, Start: record.Timestamp, End: end})
+		}
+
+	case len(i.StatusHistory) > 0:
+		for j, record := range i.StatusHistory {
+			end := observedUntil
+			if j+1 < len(i.StatusHistory) {
+				end = i.StatusHistory[j+1].Timestamp
+			}
+			periods = append(periods, StatusPeriod{Status: record.Status, PowerState: record.PowerState, Start: record.Timestamp, End: end})
+		}
+
+	default:
+		periods = append(periods, StatusPeriod{Status: i.CurrentStatus, PowerState: i.CurrentPowerState, Start: i.CreatedAt, End: observedUntil})
+	}
+
+	// 삭제 이후의 기록은 과금 대상이 아님
+	if i.DeletedAt != nil {
+		for j := range periods {
+			if periods[j].End.After(*i.DeletedAt) {
+				periods[j].End = *i.DeletedAt
+			}
+		}
+	}
+
+	return periods
+}
+
+// ObservedUntil is how long the current state is known to have lasted: the
+// last time the collector saw the instance, or its last update when that is
+// later or the instance has been deleted.
+func (i *InstanceState) ObservedUntil() time.Time {
+	if i.DeletedAt == nil && i.LastSeen.After(i.LastUpdated) {
+		return i.LastSeen
+	}
+	return i.LastUpdated
+}
+
+// GetStatusDurations returns the total time spent in each effective status
+func (i *InstanceState) GetStatusDurations() map[string]time.Duration {
+	durations := make(map[string]time.Duration)
+	for _, period := range i.StatusPeriods() {
+		if period.End.After(period.Start) {
+			durations[EffectiveStatus(period.Status, period.PowerState)] += period.End.Sub(period.Start)
+		}
+	}
+	return durations
+}
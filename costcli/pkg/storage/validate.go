@@ -0,0 +1,692 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"costcli/pkg/money"
+)
+
+// Validation severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ValidationIssue is a problem found in a pricing file. Path is the dotted
+// JSON path of the offending value and Line its line in the file (0 when
+// unknown).
+type ValidationIssue struct {
+	Severity string `json:"severity"`
+	Path     string `json:"path"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Longest month and year in hours, used to sanity-check monthly and yearly
+// prices against hourly ones
+const (
+	monthlyHoursCap = 31 * 24
+	yearlyHoursCap  = 366 * 24
+)
+
+// pricingValidator collects issues while walking a pricing file
+type pricingValidator struct {
+	lines   map[string]int
+	issues  []ValidationIssue
+	ruleIDs map[string]string
+}
+
+// ValidatePricing checks a pricing file for structural and semantic errors:
+// JSON syntax and types, unknown fields, missing default currency prices,
+// inconsistent monthly/yearly prices, unknown regions, invalid dates,
+// duplicate rule IDs and unknown condition types or operators.
+func ValidatePricing(data []byte) []ValidationIssue {
+	v := &pricingValidator{ruleIDs: make(map[string]string)}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		issue := ValidationIssue{Severity: SeverityError, Message: fmt.Sprintf("JSON 파싱 실패: %v", err)}
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			issue.Line = lineAt(data, syntaxErr.Offset)
+		}
+		return []ValidationIssue{issue}
+	}
+	v.lines = jsonLineIndex(data)
+
+	csps, _ := raw["csps"].(map[string]any)
+	switch {
+	case len(csps) > 0:
+		var schema NewPricingSchema
+		v.typeErrors(data, json.Unmarshal(data, &schema))
+		v.unknownFields(raw, reflect.TypeOf(schema), "")
+		v.validateSchema(&schema)
+	case raw["flavors"] != nil:
+		var legacy LegacyPricingStorage
+		v.typeErrors(data, json.Unmarshal(data, &legacy))
+		v.unknownFields(raw, reflect.TypeOf(legacy), "")
+		v.validateLegacy(&legacy)
+	default:
+		v.errorf("", "알 수 없는 JSON 형식: csps(새 형식) 또는 flavors(기존 형식)가 필요합니다")
+	}
+
+	slices.SortStableFunc(v.issues, func(a, b ValidationIssue) int {
+		return a.Line - b.Line
+	})
+	return v.issues
+}
+
+// HasErrors reports whether any issue is an error rather than a warning
+func HasErrors(issues []ValidationIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *pricingValidator) add(severity, path, format string, args ...any) {
+	v.issues = append(v.issues, ValidationIssue{
+		Severity: severity,
+		Path:     path,
+		Line:     v.lineOf(path),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *pricingValidator) errorf(path, format string, args ...any) {
+	v.add(SeverityError, path, format, args...)
+}
+
+func (v *pricingValidator) warnf(path, format string, args ...any) {
+	v.add(SeverityWarning, path, format, args...)
+}
+
+// lineOf returns the line of a path, or of its closest ancestor present in
+// the file
+func (v *pricingValidator) lineOf(path string) int {
+	for path != "" {
+		if line, exists := v.lines[path]; exists {
+			return line
+		}
+		cut := max(strings.LastIndex(path, "."), strings.LastIndex(path, "["))
+		if cut < 0 {
+			break
+		}
+		path = path[:cut]
+	}
+	return 0
+}
+
+// typeErrors reports a value whose JSON type does not match the schema
+func (v *pricingValidator) typeErrors(data []byte, err error) {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		v.issues = append(v.issues, ValidationIssue{
+			Severity: SeverityError,
+			Path:     typeErr.Field,
+			Line:     lineAt(data, typeErr.Offset),
+			Message:  fmt.Sprintf("%s 값이 필요하지만 %s 값입니다", typeErr.Type, typeErr.Value),
+		})
+	} else if err != nil {
+		v.errorf("", "JSON 파싱 실패: %v", err)
+	}
+}
+
+// unknownFields warns about keys the schema does not define. They are kept
+// when the file is edited but are otherwise ignored, so they are often typos.
+func (v *pricingValidator) unknownFields(raw any, t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return
+		}
+		fields := make(map[string]reflect.Type)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			fields[name] = field.Type
+		}
+		for key, value := range object {
+			fieldType, exists := fields[key]
+			if !exists {
+				v.warnf(joinPath(path, key), "알 수 없는 필드 %q (무시됨)", key)
+				continue
+			}
+			v.unknownFields(value, fieldType, joinPath(path, key))
+		}
+
+	case reflect.Map:
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return
+		}
+		for key, value := range object {
+			v.unknownFields(value, t.Elem(), joinPath(path, key))
+		}
+
+	case reflect.Slice:
+		list, ok := raw.([]any)
+		if !ok {
+			return
+		}
+		for i, value := range list {
+			v.unknownFields(value, t.Elem(), indexPath(path, i))
+		}
+	}
+}
+
+func (v *pricingValidator) validateLegacy(legacy *LegacyPricingStorage) {
+	if len(legacy.Flavors) == 0 {
+		v.errorf("flavors", "flavor 가격이 없습니다")
+	}
+	for _, flavorID := range sortedKeys(legacy.Flavors) {
+		price := legacy.Flavors[flavorID]
+		path := joinPath("flavors", flavorID)
+		if price == nil {
+			v.errorf(path, "가격 정보가 비어 있습니다")
+			continue
+		}
+		if price.FlavorID != "" && price.FlavorID != flavorID {
+			v.warnf(joinPath(path, "flavor_id"), "flavor_id %q가 키 %q와 다릅니다", price.FlavorID, flavorID)
+		}
+		if price.Currency == "" {
+			v.errorf(joinPath(path, "currency"), "통화가 지정되지 않았습니다")
+		}
+		v.validatePrices(path, "hourly_price", "monthly_price", "yearly_price", price.HourlyPrice, price.MonthlyPrice, price.YearlyPrice)
+	}
+}
+
+func (v *pricingValidator) validateSchema(schema *NewPricingSchema) {
+	if schema.Version == "" {
+		v.warnf("version", "version이 지정되지 않았습니다")
+	}
+	if schema.DefaultCSP != "" {
+		if _, exists := schema.CSPs[schema.DefaultCSP]; !exists {
+			v.errorf("default_csp", "default_csp %q가 csps에 없습니다", schema.DefaultCSP)
+		}
+	} else if _, exists := schema.CSPs["nhn"]; !exists {
+		v.errorf("default_csp", "default_csp가 없고 기본값 nhn도 csps에 없습니다")
+	}
+
+	for _, cspName := range sortedKeys(schema.CSPs) {
+		csp := schema.CSPs[cspName]
+		v.validateCSP(joinPath("csps", cspName), csp)
+	}
+
+	for i, rule := range schema.GlobalDiscountRules {
+		v.validateRule(indexPath("global_discount_rules", i), rule)
+	}
+
+	conversion := schema.GlobalSettings.CurrencyConversion
+	for _, key := range sortedKeys(conversion.Rates) {
+		path := joinPath("global_settings.currency_conversion.rates", key)
+		if _, _, ok := parseRateKey(key); !ok {
+			v.errorf(path, "환율 키 %q는 FROM_to_TO 형식이어야 합니다", key)
+		}
+		if conversion.Rates[key] <= 0 {
+			v.errorf(path, "환율은 0보다 커야 합니다")
+		}
+	}
+
+	policy := schema.GlobalSettings.DiscountPolicy
+	v.validateEnum("global_settings.discount_policy.default_stack_mode", policy.DefaultStackMode, StackModeAdditive, StackModeCompound)
+	if limit := policy.MaxTotalDiscountPercent; limit != nil && (*limit < 0 || *limit > 100) {
+		v.errorf("global_settings.discount_policy.max_total_discount_percent", "0에서 100 사이여야 합니다")
+	}
+}
+
+func (v *pricingValidator) validateCSP(path string, csp CSPProvider) {
+	if csp.DefaultCurrency == "" {
+		v.errorf(joinPath(path, "default_currency"), "기본 통화가 지정되지 않았습니다")
+	}
+
+	regions := make(map[string]bool)
+	for i, region := range csp.Regions {
+		if region.Code == "" {
+			v.errorf(joinPath(indexPath(joinPath(path, "regions"), i), "code"), "리전 코드가 없습니다")
+		}
+		regions[region.Code] = true
+	}
+
+	v.validateEnum(joinPath(path, "billing_model"), csp.BillingModel, BillingModelHourly, BillingModelMonthlyCap)
+	v.validateEnum(joinPath(path, "contract"), csp.Contract, ContractMonthly, ContractYearly)
+
+	rules := csp.BillingRules
+	v.validateEnum(joinPath(path, "billing_rules.granularity"), rules.Granularity, GranularitySecond, GranularityMinute, GranularityHour)
+	if rules.MinimumBillableSeconds < 0 {
+		v.errorf(joinPath(path, "billing_rules.minimum_billable_seconds"), "0 이상이어야 합니다")
+	}
+	for _, currency := range sortedKeys(rules.Rounding) {
+		rounding := rules.Rounding[currency]
+		roundingPath := joinPath(path, "billing_rules.rounding."+currency)
+		if rounding.Decimals < -6 || rounding.Decimals > 6 {
+			v.errorf(joinPath(roundingPath, "decimals"), "-6에서 6 사이여야 합니다")
+		}
+		v.validateEnum(joinPath(roundingPath, "mode"), rounding.Mode, money.RoundHalfUp, money.RoundUp, money.RoundDown)
+	}
+
+	if billing := csp.ShutdownBilling; billing != nil {
+		billingPath := joinPath(path, "shutdown_billing")
+		if billing.RatePercent < 0 || billing.RatePercent > 100 {
+			v.errorf(joinPath(billingPath, "rate_percent"), "0에서 100 사이여야 합니다")
+		}
+		if billing.EligibleDays < 0 {
+			v.errorf(joinPath(billingPath, "eligible_days"), "0 이상이어야 합니다")
+		}
+		if billing.AfterWindowPercent != nil && *billing.AfterWindowPercent < 0 {
+			v.errorf(joinPath(billingPath, "after_window_percent"), "0 이상이어야 합니다")
+		}
+	}
+
+	for _, status := range sortedKeys(csp.StatusBilling) {
+		statusPath := joinPath(path, "status_billing."+status)
+		if _, known := DefaultStatusBilling[status]; !known && status != StatusUnknown {
+			v.warnf(statusPath, "알 수 없는 상태 %q", status)
+		}
+		if !slices.Contains(BillingClasses, csp.StatusBilling[status]) {
+			v.errorf(statusPath, "알 수 없는 과금 구분 %q (가능한 값: %s)", csp.StatusBilling[status], strings.Join(BillingClasses, ", "))
+		}
+	}
+	if csp.StorageGBHourly < 0 {
+		v.errorf(joinPath(path, "storage_gb_hourly"), "0 이상이어야 합니다")
+	}
+	if fallback := csp.FallbackPricing; fallback != nil {
+		if fallback.VCPUHourly < 0 {
+			v.errorf(joinPath(path, "fallback_pricing.vcpu_hourly"), "0 이상이어야 합니다")
+		}
+		if fallback.MemoryGBHourly < 0 {
+			v.errorf(joinPath(path, "fallback_pricing.memory_gb_hourly"), "0 이상이어야 합니다")
+		}
+		if fallback.VCPUHourly == 0 && fallback.MemoryGBHourly == 0 {
+			v.warnf(joinPath(path, "fallback_pricing"), "단가가 모두 0이면 추정 가격이 0이 됩니다")
+		}
+	}
+
+	if len(csp.InstanceTypes) == 0 {
+		v.warnf(joinPath(path, "instance_types"), "등록된 인스턴스 타입이 없습니다")
+	}
+	for _, flavorID := range sortedKeys(csp.InstanceTypes) {
+		v.validateInstanceType(joinPath(path, "instance_types."+flavorID), flavorID, csp, regions)
+	}
+
+	for i, rule := range csp.DiscountRules {
+		v.validateRule(indexPath(joinPath(path, "discount_rules"), i), rule)
+	}
+}
+
+func (v *pricingValidator) validateInstanceType(path, flavorID string, csp CSPProvider, regions map[string]bool) {
+	instanceType := csp.InstanceTypes[flavorID]
+
+	if instanceType.ID != "" && instanceType.ID != flavorID {
+		v.warnf(joinPath(path, "id"), "id %q가 키 %q와 다릅니다", instanceType.ID, flavorID)
+	}
+	if instanceType.Name == "" {
+		v.warnf(joinPath(path, "name"), "이름이 없습니다")
+	}
+	v.validateEnum(joinPath(path, "contract"), instanceType.Contract, ContractMonthly, ContractYearly)
+
+	v.validatePricingMap(joinPath(path, "pricing"), instanceType.Pricing, csp.DefaultCurrency)
+
+	contract := instanceType.Contract
+	if contract == "" {
+		contract = csp.Contract
+	}
+	if pricing, exists := instanceType.Pricing[csp.DefaultCurrency]; exists {
+		pricingPath := joinPath(path, "pricing."+csp.DefaultCurrency)
+		if contract == ContractMonthly && pricing.Monthly <= 0 {
+			v.errorf(joinPath(pricingPath, "monthly"), "월 약정이지만 월 요금이 없어 시간제로 계산됩니다")
+		}
+		if contract == ContractYearly && pricing.Yearly <= 0 {
+			v.errorf(joinPath(pricingPath, "yearly"), "연 약정이지만 연 요금이 없어 시간제로 계산됩니다")
+		}
+	}
+
+	for i, region := range instanceType.Availability {
+		if len(regions) > 0 && !regions[region] {
+			v.errorf(indexPath(joinPath(path, "availability"), i), "알 수 없는 리전 %q", region)
+		}
+	}
+	v.validateRegionPricing(joinPath(path, "region_pricing"), instanceType, instanceType.RegionPricing, csp.DefaultCurrency, regions)
+
+	previous := ""
+	for i, version := range instanceType.PriceHistory {
+		versionPath := indexPath(joinPath(path, "price_history"), i)
+		effectiveTo, err := ParseEffectiveTime(version.EffectiveTo)
+		if err != nil {
+			v.errorf(joinPath(versionPath, "effective_to"), "%v", err)
+			continue
+		}
+		if previous != "" {
+			if before, err := ParseEffectiveTime(previous); err == nil && !effectiveTo.After(before) {
+				v.warnf(joinPath(versionPath, "effective_to"), "이전 항목(%s)보다 늦은 날짜여야 합니다", previous)
+			}
+		}
+		previous = version.EffectiveTo
+		v.validatePricingMap(joinPath(versionPath, "pricing"), version.Pricing, csp.DefaultCurrency)
+		v.validateRegionPricing(joinPath(versionPath, "region_pricing"), instanceType, version.RegionPricing, csp.DefaultCurrency, regions)
+	}
+}
+
+func (v *pricingValidator) validateRegionPricing(path string, instanceType InstanceType, regionPricing map[string]map[string]Pricing, defaultCurrency string, regions map[string]bool) {
+	for _, region := range sortedKeys(regionPricing) {
+		regionPath := joinPath(path, region)
+		if len(regions) > 0 && !regions[region] {
+			v.errorf(regionPath, "알 수 없는 리전 %q", region)
+		} else if !instanceType.AvailableIn(region) {
+			v.warnf(regionPath, "availability에 없는 리전입니다")
+		}
+		v.validatePricingMap(regionPath, regionPricing[region], defaultCurrency)
+	}
+}
+
+func (v *pricingValidator) validatePricingMap(path string, pricing map[string]Pricing, defaultCurrency string) {
+	if len(pricing) == 0 {
+		v.errorf(path, "가격이 없습니다")
+		return
+	}
+	if defaultCurrency != "" {
+		if _, exists := pricing[defaultCurrency]; !exists {
+			v.errorf(path, "기본 통화(%s) 가격이 없습니다", defaultCurrency)
+		}
+	}
+	for _, currency := range sortedKeys(pricing) {
+		price := pricing[currency]
+		currencyPath := joinPath(path, currency)
+		v.validatePrices(currencyPath, "hourly", "monthly", "yearly", price.Hourly, price.Monthly, price.Yearly)
+		if price.ShutdownHourly != nil && (*price.ShutdownHourly < 0 || *price.ShutdownHourly > price.Hourly) {
+			v.errorf(joinPath(currencyPath, "shutdown_hourly"), "0 이상, 시간당 요금 이하여야 합니다")
+		}
+		if price.StorageHourly != nil && *price.StorageHourly < 0 {
+			v.errorf(joinPath(currencyPath, "storage_hourly"), "0 이상이어야 합니다")
+		}
+	}
+}
+
+// validatePrices checks that the hourly price is positive and that monthly and
+// yearly prices are consistent with it
+func (v *pricingValidator) validatePrices(path, hourlyKey, monthlyKey, yearlyKey string, hourly, monthly, yearly float64) {
+	if hourly <= 0 {
+		v.errorf(joinPath(path, hourlyKey), "시간당 요금은 0보다 커야 합니다")
+	}
+	if monthly < 0 {
+		v.errorf(joinPath(path, monthlyKey), "0 이상이어야 합니다")
+	}
+	if yearly < 0 {
+		v.errorf(joinPath(path, yearlyKey), "0 이상이어야 합니다")
+	}
+
+	if hourly > 0 && monthly > 0 {
+		if monthly > hourly*monthlyHoursCap {
+			v.warnf(joinPath(path, monthlyKey), "월 요금(%.2f)이 시간당 요금 × %d시간(%.2f)보다 큽니다", monthly, monthlyHoursCap, hourly*monthlyHoursCap)
+		}
+		if monthly < hourly {
+			v.warnf(joinPath(path, monthlyKey), "월 요금(%.2f)이 시간당 요금(%.2f)보다 작습니다", monthly, hourly)
+		}
+	}
+	if hourly > 0 && yearly > hourly*yearlyHoursCap {
+		v.warnf(joinPath(path, yearlyKey), "연 요금(%.2f)이 시간당 요금 × %d시간(%.2f)보다 큽니다", yearly, yearlyHoursCap, hourly*yearlyHoursCap)
+	}
+	if monthly > 0 && yearly > 0 && yearly < monthly {
+		v.warnf(joinPath(path, yearlyKey), "연 요금(%.2f)이 월 요금(%.2f)보다 작습니다", yearly, monthly)
+	}
+}
+
+func (v *pricingValidator) validateRule(path string, rule DiscountRule) {
+	switch {
+	case rule.ID == "":
+		v.errorf(joinPath(path, "id"), "규칙 ID가 없습니다")
+	case v.ruleIDs[rule.ID] != "":
+		v.errorf(joinPath(path, "id"), "규칙 ID %q가 중복됩니다 (%s)", rule.ID, v.ruleIDs[rule.ID])
+	default:
+		v.ruleIDs[rule.ID] = path
+	}
+	if rule.Name == "" {
+		v.warnf(joinPath(path, "name"), "규칙 이름이 없습니다")
+	}
+
+	v.validateEnum(joinPath(path, "method"), rule.Method, DiscountMethodPercent, DiscountMethodFixed, DiscountMethodTiered)
+	v.validateEnum(joinPath(path, "scope"), rule.Scope, DiscountScopeInstance, DiscountScopeSummary)
+	v.validateEnum(joinPath(path, "stack_mode"), rule.StackMode, StackModeAdditive, StackModeCompound)
+	v.validateEnum(joinPath(path, "amount_per"), rule.AmountPer, AmountPerPeriod, AmountPerMonth)
+
+	switch rule.Method {
+	case DiscountMethodFixed:
+		if rule.DiscountAmount <= 0 {
+			v.errorf(joinPath(path, "discount_amount"), "고정 금액 할인은 discount_amount가 0보다 커야 합니다")
+		}
+	case DiscountMethodTiered:
+		if len(rule.Tiers) == 0 {
+			v.errorf(joinPath(path, "tiers"), "구간별 할인은 tiers가 필요합니다")
+		}
+		previous := 0.0
+		for i, tier := range rule.Tiers {
+			tierPath := indexPath(joinPath(path, "tiers"), i)
+			if tier.DiscountPercent < 0 || tier.DiscountPercent > 100 {
+				v.errorf(joinPath(tierPath, "discount_percent"), "0에서 100 사이여야 합니다")
+			}
+			if tier.UpToHours == nil {
+				if i != len(rule.Tiers)-1 {
+					v.errorf(joinPath(tierPath, "up_to_hours"), "상한이 없는 구간은 마지막이어야 합니다")
+				}
+				continue
+			}
+			if *tier.UpToHours <= previous {
+				v.errorf(joinPath(tierPath, "up_to_hours"), "구간 상한은 이전 구간(%.2f)보다 커야 합니다", previous)
+			}
+			previous = *tier.UpToHours
+		}
+	default:
+		if rule.DiscountPercent < 0 || rule.DiscountPercent > 100 {
+			v.errorf(joinPath(path, "discount_percent"), "0에서 100 사이여야 합니다")
+		}
+	}
+	if rule.MaxDiscountAmount < 0 {
+		v.errorf(joinPath(path, "max_discount_amount"), "0 이상이어야 합니다")
+	}
+
+	if from, to, err := rule.EffectiveWindow(); err != nil {
+		field := "effective_from"
+		if strings.HasPrefix(err.Error(), "effective_to") {
+			field = "effective_to"
+		}
+		v.errorf(joinPath(path, field), "%v", err)
+	} else if !from.IsZero() && !to.IsZero() && !to.After(from) {
+		v.errorf(joinPath(path, "effective_to"), "effective_to는 effective_from보다 늦어야 합니다")
+	}
+
+	for i := range rule.Conditions {
+		v.validateCondition(indexPath(joinPath(path, "conditions"), i), &rule.Conditions[i])
+	}
+}
+
+func (v *pricingValidator) validateCondition(path string, condition *DiscountCondition) {
+	if condition.IsGroup() {
+		if condition.Type != "" {
+			v.errorf(joinPath(path, "type"), "all/any/not 그룹에는 type을 함께 지정할 수 없습니다")
+		}
+		for i := range condition.All {
+			v.validateCondition(indexPath(joinPath(path, "all"), i), &condition.All[i])
+		}
+		for i := range condition.Any {
+			v.validateCondition(indexPath(joinPath(path, "any"), i), &condition.Any[i])
+		}
+		if condition.Not != nil {
+			v.validateCondition(joinPath(path, "not"), condition.Not)
+		}
+		return
+	}
+
+	kind, known := ConditionTypes[condition.Type]
+	if !known {
+		v.errorf(joinPath(path, "type"), "알 수 없는 조건 타입 %q", condition.Type)
+		return
+	}
+	if !slices.Contains(ConditionOperators[kind], condition.Operator) {
+		v.errorf(joinPath(path, "operator"), "%s 조건에 사용할 수 없는 연산자 %q (가능한 값: %s)", condition.Type, condition.Operator, strings.Join(ConditionOperators[kind], ", "))
+		return
+	}
+	if condition.Type == "metadata" && condition.Key == "" {
+		v.errorf(joinPath(path, "key"), "metadata 조건에는 key가 필요합니다")
+	}
+
+	valuePath := joinPath(path, "value")
+	if condition.Value == nil {
+		v.errorf(valuePath, "조건 값이 없습니다")
+		return
+	}
+
+	values := []any{condition.Value}
+	if condition.Operator == "in" || condition.Operator == "not_in" {
+		list, ok := condition.Value.([]any)
+		if !ok {
+			v.warnf(valuePath, "%s 연산자에는 목록 값을 사용하세요", condition.Operator)
+		} else {
+			values = list
+		}
+	}
+
+	for _, value := range values {
+		switch {
+		case condition.Operator == "matches":
+			pattern, ok := value.(string)
+			if !ok {
+				v.errorf(valuePath, "matches 연산자에는 정규식 문자열이 필요합니다")
+			} else if _, err := regexp.Compile(pattern); err != nil {
+				v.errorf(valuePath, "정규식 오류: %v", err)
+			}
+		case kind == ConditionKindNumber:
+			if !isNumberValue(value) {
+				v.errorf(valuePath, "%s 조건에는 숫자 값이 필요합니다 (%v)", condition.Type, value)
+			}
+		}
+	}
+}
+
+// validateEnum reports a value outside the allowed set. Empty means default.
+func (v *pricingValidator) validateEnum(path, value string, allowed ...string) {
+	if value != "" && !slices.Contains(allowed, value) {
+		v.errorf(path, "알 수 없는 값 %q (가능한 값: %s)", value, strings.Join(allowed, ", "))
+	}
+}
+
+func isNumberValue(value any) bool {
+	switch v := value.(type) {
+	case float64:
+		return true
+	case string:
+		_, err := strconv.ParseFloat(v, 64)
+		return err == nil
+	default:
+		return false
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func indexPath(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// jsonLineIndex maps the dotted path of every value in a JSON document to the
+// line it starts on
+func jsonLineIndex(data []byte) map[string]int {
+	lines := make(map[string]int)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	indexJSONValue(decoder, data, "", lines)
+	return lines
+}
+
+func indexJSONValue(decoder *json.Decoder, data []byte, path string, lines map[string]int) bool {
+	token, err := decoder.Token()
+	if err != nil {
+		return false
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return true
+	}
+
+	switch delim {
+	case '{':
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return false
+			}
+			name, _ := key.(string)
+			childPath := joinPath(path, name)
+			lines[childPath] = lineAt(data, decoder.InputOffset())
+			if !indexJSONValue(decoder, data, childPath, lines) {
+				return false
+			}
+		}
+	case '[':
+		for i := 0; decoder.More(); i++ {
+			childPath := indexPath(path, i)
+			lines[childPath] = lineAt(data, nextValueOffset(data, decoder.InputOffset()))
+			if !indexJSONValue(decoder, data, childPath, lines) {
+				return false
+			}
+		}
+	}
+
+	_, err = decoder.Token() // 닫는 괄호
+	return err == nil
+}
+
+// nextValueOffset skips whitespace and separators to the start of the next value
+func nextValueOffset(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// lineAt returns the 1-based line number of a byte offset
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
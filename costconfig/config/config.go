@@ -0,0 +1,171 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+type Config struct {
+	NHNCloud   NHNCloudConfig   `json:"nhn_cloud"`
+	AWS        AWSConfig        `json:"aws,omitzero"`
+	NaverCloud NaverCloudConfig `json:"naver_cloud,omitzero"`
+	Monitor    MonitorConfig    `json:"monitor"`
+	Storage    StorageConfig    `json:"storage"`
+}
+
+type NHNCloudConfig struct {
+	TenantID        string `json:"tenant_id"`
+	Username        string `json:"username"`
+	Password        string `json:"password"`
+	PasswordFile    string `json:"password_file,omitempty"`    // 비밀번호만 담은 파일 (권한 0600)
+	PasswordCommand string `json:"password_command,omitempty"` // 비밀번호를 출력하는 명령
+	Region          string `json:"region"`
+	IdentityURL     string `json:"identity_url"`
+	ComputeURL      string `json:"compute_url,omitempty"` // cost-collect에서 사용
+}
+
+// AWSConfig holds the credentials and regions for the EC2 collector
+type AWSConfig struct {
+	AccessKeyID            string   `json:"access_key_id"`
+	SecretAccessKey        string   `json:"secret_access_key"`
+	SecretAccessKeyFile    string   `json:"secret_access_key_file,omitempty"`
+	SecretAccessKeyCommand string   `json:"secret_access_key_command,omitempty"`
+	SessionToken           string   `json:"session_token,omitempty"`
+	Regions                []string `json:"regions"`
+	Endpoint               string   `json:"endpoint,omitempty"` // EC2 호환 엔드포인트 (비어 있으면 리전별 AWS 엔드포인트)
+}
+
+// NaverCloudConfig holds the API keys and regions for the NCP collector
+type NaverCloudConfig struct {
+	AccessKey        string   `json:"access_key"`
+	SecretKey        string   `json:"secret_key"`
+	SecretKeyFile    string   `json:"secret_key_file,omitempty"`
+	SecretKeyCommand string   `json:"secret_key_command,omitempty"`
+	Regions          []string `json:"regions"`
+	Endpoint         string   `json:"endpoint,omitempty"` // API Gateway 주소 (비어 있으면 ncloud.apigw.ntruss.com)
+}
+
+// DefaultIntervalMinutes is the collection interval used when none is set
+const DefaultIntervalMinutes = 15
+
+type MonitorConfig struct {
+	IntervalMinutes int  `json:"interval_minutes"`
+	AutoStart       bool `json:"auto_start"`
+}
+
+type StorageConfig struct {
+	DataDir      string `json:"data_dir"`
+	InstanceFile string `json:"instance_file"`
+	PriceFile    string `json:"price_file"`
+	CreditFile   string `json:"credit_file,omitempty"` // costcli에서 사용
+	FlavorFile   string `json:"flavor_file,omitempty"`
+}
+
+// Configured reports whether NHN Cloud credentials are set
+func (c *NHNCloudConfig) Configured() bool {
+	return c.TenantID != "" && c.Username != "" && c.Password != ""
+}
+
+// Configured reports whether AWS credentials are set
+func (c *AWSConfig) Configured() bool {
+	return c.AccessKeyID != "" && c.SecretAccessKey != ""
+}
+
+// Configured reports whether NCP API keys are set
+func (c *NaverCloudConfig) Configured() bool {
+	return c.AccessKey != "" && c.SecretKey != ""
+}
+
+// ConfigFilePath returns configPath, or the config file of the profile
+// selected by COSTCTL_PROFILE when it is empty
+func ConfigFilePath(configPath string) (string, error) {
+	if configPath != "" {
+		return configPath, nil
+	}
+	return ProfilePath(ActiveProfile(""))
+}
+
+// LoadConfig reads the config file, failing with every invalid value when
+// it does not pass validation
+func LoadConfig(configPath string) (*Config, error) {
+	configPath, err := ConfigFilePath(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config, problems, err := ReadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Path: configPath, Problems: problems}
+	}
+
+	return config, nil
+}
+
+// ReadConfig reads the config file without rejecting invalid values, so that
+// config set can fix them. problems lists the unknown keys, values of the
+// wrong type and values that fail Validate.
+func ReadConfig(configPath string) (*Config, []Problem, error) {
+	configPath, err := ConfigFilePath(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("설정 파일 읽기 실패: %w", err)
+	}
+	warnPermissions(configPath)
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("설정 파일 파싱 실패: %w", err)
+	}
+	problems := checkFields(raw, reflect.TypeOf(Config{}), "")
+
+	// 형식이 잘못된 값은 위에서 보고했으므로 해당 필드만 비운 채로 계속 읽음
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil && len(problems) == 0 {
+		return nil, nil, fmt.Errorf("설정 파일 파싱 실패: %w", err)
+	}
+
+	reported := make(map[string]bool, len(problems))
+	for _, problem := range problems {
+		reported[problem.Key] = true
+	}
+	for _, problem := range config.Validate() {
+		if !reported[problem.Key] {
+			problems = append(problems, problem)
+		}
+	}
+	sortProblems(problems)
+
+	return &config, problems, nil
+}
+
+func (c *Config) Save(configPath string) error {
+	dir := filepath.Dir(configPath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("디렉토리 생성 실패: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("설정 직렬화 실패: %w", err)
+	}
+
+	// 인증 정보가 들어 있으므로 소유자만 읽고 쓸 수 있게 저장 (기존 파일의 권한도 바로잡음)
+	if err := os.WriteFile(configPath, data, 0600); err != nil {
+		return fmt.Errorf("설정 파일 저장 실패: %w", err)
+	}
+	if err := os.Chmod(configPath, 0600); err != nil {
+		return fmt.Errorf("설정 파일 권한 변경 실패: %w", err)
+	}
+
+	return nil
+}
\ No newline at end of file
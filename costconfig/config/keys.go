@@ -0,0 +1,254 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Config keys are the dotted JSON names of the fields, such as
+// monitor.interval_minutes or aws.regions. A key that names a section
+// (aws) addresses the whole section.
+
+// keyAliases are the section names accepted by earlier versions of config set
+var keyAliases = map[string]string{
+	"nhn": "nhn_cloud",
+}
+
+// secretKeys are the keys whose values are never echoed, with the
+// environment variable that can provide them instead
+var secretKeys = map[string]string{
+	"nhn_cloud.password":     EnvNHNPassword,
+	"aws.secret_access_key":  EnvAWSSecretAccessKey,
+	"aws.session_token":      EnvAWSSessionToken,
+	"naver_cloud.secret_key": EnvNaverSecretKey,
+}
+
+// Keys returns the key of every config value, in file order
+func Keys() []string {
+	var keys []string
+	var walk func(t reflect.Type, prefix string)
+	walk = func(t reflect.Type, prefix string) {
+		for i := 0; i < t.NumField(); i++ {
+			name, ok := jsonName(t.Field(i))
+			if !ok {
+				continue
+			}
+			if t.Field(i).Type.Kind() == reflect.Struct {
+				walk(t.Field(i).Type, prefix+name+".")
+				continue
+			}
+			keys = append(keys, prefix+name)
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "")
+	return keys
+}
+
+// CanonicalKey resolves aliases such as nhn.password to the key of the
+// field, failing for keys that do not exist
+func CanonicalKey(key string) (string, error) {
+	_, canonical, err := (&Config{}).lookup(key)
+	return canonical, err
+}
+
+// IsSecretKey reports whether key holds a credential, returning the
+// environment variable that can provide it
+func IsSecretKey(key string) (string, bool) {
+	canonical, err := CanonicalKey(key)
+	if err != nil {
+		return "", false
+	}
+	env, ok := secretKeys[canonical]
+	return env, ok
+}
+
+// Get returns the value of key. Lists are joined with commas and sections
+// are returned as JSON. Secrets are masked, also inside sections, so that
+// no command prints them.
+func (c *Config) Get(key string) (string, error) {
+	field, canonical, err := c.lookup(key)
+	if err != nil {
+		return "", err
+	}
+
+	switch field.Kind() {
+	case reflect.Struct:
+		section := reflect.New(field.Type()).Elem()
+		section.Set(field)
+		for secretKey := range secretKeys {
+			name, ok := strings.CutPrefix(secretKey, canonical+".")
+			if !ok {
+				continue
+			}
+			if secret, ok := structField(section, name); ok && secret.String() != "" {
+				secret.SetString(MaskSecret(secret.String()))
+			}
+		}
+		data, err := json.MarshalIndent(section.Interface(), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("%s 직렬화 실패: %w", key, err)
+		}
+		return string(data), nil
+	case reflect.Slice:
+		return strings.Join(field.Interface().([]string), ","), nil
+	}
+
+	value := fmt.Sprint(field.Interface())
+	if _, secret := secretKeys[canonical]; secret && value != "" {
+		return MaskSecret(value), nil
+	}
+	return value, nil
+}
+
+// MaskSecret hides all but the first and last two characters of a secret
+func MaskSecret(secret string) string {
+	if secret == "" {
+		return "(설정되지 않음)"
+	}
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return secret[:2] + "****" + secret[len(secret)-2:]
+}
+
+// Set parses value as the type of key and stores it. Lists are given as
+// comma separated values. The config is left unchanged when the value does
+// not pass validation.
+func (c *Config) Set(key, value string) error {
+	field, canonical, err := c.lookup(key)
+	if err != nil {
+		return err
+	}
+
+	var parsed reflect.Value
+	switch field.Kind() {
+	case reflect.String:
+		parsed = reflect.ValueOf(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%s: 정수가 필요합니다: %q", canonical, value)
+		}
+		parsed = reflect.ValueOf(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%s: true 또는 false가 필요합니다: %q", canonical, value)
+		}
+		parsed = reflect.ValueOf(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		parsed = reflect.ValueOf(items)
+	case reflect.Struct:
+		return fmt.Errorf("%s은(는) 설정 항목이 아닌 섹션입니다. 하위 키를 지정해주세요 (예: %s)", canonical, sectionExample(canonical))
+	default:
+		return fmt.Errorf("%s: 지원하지 않는 설정 형식입니다 (%s)", canonical, field.Type())
+	}
+
+	return c.replace(field, canonical, parsed)
+}
+
+// Unset resets key to its zero value, which removes optional values and
+// whole optional sections from the config file
+func (c *Config) Unset(key string) error {
+	field, canonical, err := c.lookup(key)
+	if err != nil {
+		return err
+	}
+	return c.replace(field, canonical, reflect.Zero(field.Type()))
+}
+
+// replace stores value in field, restoring the previous value when the
+// config then has a problem under key
+func (c *Config) replace(field reflect.Value, key string, value reflect.Value) error {
+	previous := reflect.New(field.Type()).Elem()
+	previous.Set(field)
+	field.Set(value)
+
+	for _, problem := range c.Validate() {
+		if problem.Key == key || strings.HasPrefix(problem.Key, key+".") {
+			field.Set(previous)
+			return fmt.Errorf("%s: %s", problem.Key, problem.Message)
+		}
+	}
+	return nil
+}
+
+// lookup finds the field addressed by key and its canonical key
+func (c *Config) lookup(key string) (reflect.Value, string, error) {
+	parts := strings.Split(key, ".")
+	if alias, ok := keyAliases[parts[0]]; ok {
+		parts[0] = alias
+	}
+
+	field := reflect.ValueOf(c).Elem()
+	for _, part := range parts {
+		if field.Kind() != reflect.Struct {
+			return reflect.Value{}, "", fmt.Errorf("알 수 없는 설정 키: %s", key)
+		}
+		next, ok := structField(field, part)
+		if !ok {
+			return reflect.Value{}, "", fmt.Errorf("알 수 없는 설정 키: %s ('config get'으로 설정 키 목록을 확인할 수 있습니다)", key)
+		}
+		field = next
+	}
+	return field, strings.Join(parts, "."), nil
+}
+
+// structField returns the field of a struct value with the given JSON name
+func structField(value reflect.Value, name string) (reflect.Value, bool) {
+	for i := 0; i < value.NumField(); i++ {
+		if fieldName, ok := jsonName(value.Type().Field(i)); ok && fieldName == name {
+			return value.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// jsonName returns the name a field is stored under in the config file
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" || !field.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// sectionExample returns the first key of a section for error messages
+func sectionExample(section string) string {
+	for _, key := range Keys() {
+		if strings.HasPrefix(key, section+".") {
+			return key
+		}
+	}
+	return section
+}
+
+// typeName describes a field type in validation messages
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "문자열"
+	case reflect.Int:
+		return "정수"
+	case reflect.Bool:
+		return "true 또는 false"
+	case reflect.Slice:
+		return "문자열 배열"
+	case reflect.Struct:
+		return "객체"
+	}
+	return t.String()
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetMasksSecrets(t *testing.T) {
+	cfg := &Config{
+		NHNCloud:   NHNCloudConfig{TenantID: "tenant", Username: "user@example.com", Password: "nhn-password-1"},
+		AWS:        AWSConfig{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "aws-secret-key-1", SessionToken: "aws-session-token-1"},
+		NaverCloud: NaverCloudConfig{AccessKey: "ncp-access", SecretKey: "ncp-secret-key-1"},
+	}
+	secrets := []string{"nhn-password-1", "aws-secret-key-1", "aws-session-token-1", "ncp-secret-key-1"}
+
+	keys := append([]string{"nhn_cloud", "nhn", "aws", "naver_cloud", "nhn.password"}, Keys()...)
+	for _, key := range keys {
+		value, err := cfg.Get(key)
+		if err != nil {
+			t.Fatalf("Get(%s): %v", key, err)
+		}
+		for _, secret := range secrets {
+			if strings.Contains(value, secret) {
+				t.Errorf("Get(%s) contains the secret %q:\n%s", key, secret, value)
+			}
+		}
+	}
+
+	if value, _ := cfg.Get("nhn_cloud"); !strings.Contains(value, `"username": "user@example.com"`) {
+		t.Errorf("Get(nhn_cloud) = %s, want the other values unmasked", value)
+	}
+	if value, _ := cfg.Get("nhn_cloud.password"); value != MaskSecret("nhn-password-1") {
+		t.Errorf("Get(nhn_cloud.password) = %q, want %q", value, MaskSecret("nhn-password-1"))
+	}
+	if cfg.NHNCloud.Password != "nhn-password-1" {
+		t.Errorf("Get changed the config: password = %q", cfg.NHNCloud.Password)
+	}
+}
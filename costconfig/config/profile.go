@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// EnvProfile selects the profile when --profile is not given
+const EnvProfile = "COSTCTL_PROFILE"
+
+// DefaultProfile is the profile stored directly in ~/.costctl
+const DefaultProfile = "default"
+
+// profileNamePattern keeps profile names usable as directory names
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ActiveProfile returns the profile given by the --profile flag, then by
+// COSTCTL_PROFILE, then the default profile
+func ActiveProfile(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if profile := os.Getenv(EnvProfile); profile != "" {
+		return profile
+	}
+	return DefaultProfile
+}
+
+// ProfileDir returns the directory holding the config file and data of a
+// profile: ~/.costctl for the default profile and ~/.costctl/profiles/<name>
+// for the others, so that their collected data never mixes
+func ProfileDir(profile string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("홈 디렉토리 조회 실패: %w", err)
+	}
+	baseDir := filepath.Join(homeDir, ".costctl")
+
+	if profile == "" || profile == DefaultProfile {
+		return baseDir, nil
+	}
+	if !profileNamePattern.MatchString(profile) {
+		return "", fmt.Errorf("잘못된 프로필 이름: %q (영문, 숫자, '.', '_', '-'만 사용할 수 있습니다)", profile)
+	}
+	return filepath.Join(baseDir, "profiles", profile), nil
+}
+
+// ProfilePath returns the config file of a profile
+func ProfilePath(profile string) (string, error) {
+	dir, err := ProfileDir(profile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// Profiles returns the profiles that have a config file, default first
+func Profiles() ([]string, error) {
+	var profiles []string
+	if path, err := ProfilePath(DefaultProfile); err != nil {
+		return nil, err
+	} else if _, err := os.Stat(path); err == nil {
+		profiles = append(profiles, DefaultProfile)
+	}
+
+	baseDir, err := ProfileDir(DefaultProfile)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(baseDir, "profiles"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("프로필 디렉토리 읽기 실패: %w", err)
+	}
+
+	var named []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(baseDir, "profiles", entry.Name(), "config.json")); err == nil {
+			named = append(named, entry.Name())
+		}
+	}
+	sort.Strings(named)
+
+	return append(profiles, named...), nil
+}
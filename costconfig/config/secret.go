@@ -0,0 +1,150 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Environment variables that override credentials in the config file
+const (
+	EnvNHNTenantID        = "COSTCTL_NHN_TENANT_ID"
+	EnvNHNUsername        = "COSTCTL_NHN_USERNAME"
+	EnvNHNPassword        = "COSTCTL_NHN_PASSWORD"
+	EnvAWSAccessKeyID     = "COSTCTL_AWS_ACCESS_KEY_ID"
+	EnvAWSSecretAccessKey = "COSTCTL_AWS_SECRET_ACCESS_KEY"
+	EnvAWSSessionToken    = "COSTCTL_AWS_SESSION_TOKEN"
+	EnvNaverAccessKey     = "COSTCTL_NAVER_ACCESS_KEY"
+	EnvNaverSecretKey     = "COSTCTL_NAVER_SECRET_KEY"
+)
+
+// secretCommandTimeout limits how long a credential helper may run
+const secretCommandTimeout = 30 * time.Second
+
+// secret describes where one credential can come from, in order of
+// precedence: environment variable, helper command, file, config value.
+type secret struct {
+	name    string
+	value   *string
+	env     string
+	file    string
+	command string
+}
+
+// ResolveSecrets fills the credentials from their environment variables,
+// helper commands and files. The resolved config must not be saved, or the
+// secrets would be written back in plaintext.
+func (c *Config) ResolveSecrets() error {
+	secrets := []secret{
+		{name: "nhn_cloud.tenant_id", value: &c.NHNCloud.TenantID, env: EnvNHNTenantID},
+		{name: "nhn_cloud.username", value: &c.NHNCloud.Username, env: EnvNHNUsername},
+		{name: "nhn_cloud.password", value: &c.NHNCloud.Password, env: EnvNHNPassword,
+			file: c.NHNCloud.PasswordFile, command: c.NHNCloud.PasswordCommand},
+		{name: "aws.access_key_id", value: &c.AWS.AccessKeyID, env: EnvAWSAccessKeyID},
+		{name: "aws.secret_access_key", value: &c.AWS.SecretAccessKey, env: EnvAWSSecretAccessKey,
+			file: c.AWS.SecretAccessKeyFile, command: c.AWS.SecretAccessKeyCommand},
+		{name: "aws.session_token", value: &c.AWS.SessionToken, env: EnvAWSSessionToken},
+		{name: "naver_cloud.access_key", value: &c.NaverCloud.AccessKey, env: EnvNaverAccessKey},
+		{name: "naver_cloud.secret_key", value: &c.NaverCloud.SecretKey, env: EnvNaverSecretKey,
+			file: c.NaverCloud.SecretKeyFile, command: c.NaverCloud.SecretKeyCommand},
+	}
+
+	for _, s := range secrets {
+		value, err := s.resolve()
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.name, err)
+		}
+		if value != "" {
+			*s.value = value
+		}
+	}
+	return nil
+}
+
+func (s secret) resolve() (string, error) {
+	if value := os.Getenv(s.env); value != "" {
+		return value, nil
+	}
+	if s.command != "" {
+		return runSecretCommand(s.command)
+	}
+	if s.file != "" {
+		return readSecretFile(s.file)
+	}
+	return "", nil
+}
+
+// runSecretCommand runs a credential helper through the shell and returns
+// its first output line
+func runSecretCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = os.Stdin // 비밀번호 관리자가 잠금 해제를 요청할 수 있도록
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("인증 정보 명령 실행 실패: %w: %s", err, message)
+		}
+		return "", fmt.Errorf("인증 정보 명령 실행 실패: %w", err)
+	}
+
+	value, _, _ := strings.Cut(stdout.String(), "\n")
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("인증 정보 명령의 출력이 비어 있습니다")
+	}
+	return value, nil
+}
+
+// readSecretFile reads a secret from a file that only its owner can access
+func readSecretFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("인증 정보 파일 확인 실패: %w", err)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("인증 정보 파일 %s의 권한이 %04o입니다. 'chmod 600 %s'로 다른 사용자의 접근을 막아야 합니다", path, info.Mode().Perm(), path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("인증 정보 파일 읽기 실패: %w", err)
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", fmt.Errorf("인증 정보 파일 %s이(가) 비어 있습니다", path)
+	}
+	return value, nil
+}
+
+// warnPermissions warns when the config file can be read by other users
+func warnPermissions(path string) {
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm()&0077 == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "경고: 설정 파일 %s의 권한이 %04o입니다. 인증 정보가 노출되지 않도록 'chmod 600 %s'를 실행하세요\n",
+		path, info.Mode().Perm(), path)
+}
+
+// SecretSource describes where a credential is read from when it is not
+// the config file itself, for config show. It returns "" otherwise.
+func SecretSource(env, file, command string) string {
+	switch {
+	case os.Getenv(env) != "":
+		return "환경 변수 " + env
+	case command != "":
+		return "명령: " + command
+	case file != "":
+		return "파일: " + file
+	}
+	return ""
+}
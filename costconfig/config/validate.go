@@ -0,0 +1,149 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Problem is an invalid value in the config file
+type Problem struct {
+	Key     string
+	Message string
+
+	// 알 수 없는 키이거나 형식이 맞지 않아 읽지 못한 값으로, 설정을 저장하면 사라짐
+	Discarded bool
+}
+
+// ValidationError lists every problem found in a config file, so that they
+// can be fixed at once
+type ValidationError struct {
+	Path     string
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "설정 파일 %s에 잘못된 값이 %d개 있습니다", e.Path, len(e.Problems))
+	for _, problem := range e.Problems {
+		fmt.Fprintf(&b, "\n  - %s: %s", problem.Key, problem.Message)
+	}
+	b.WriteString("\n'config set'이나 'config unset'으로 값을 수정한 뒤 'config validate'로 확인하세요")
+	return b.String()
+}
+
+// Validate checks the values that the JSON types cannot express
+func (c *Config) Validate() []Problem {
+	var problems []Problem
+	add := func(key, format string, args ...interface{}) {
+		problems = append(problems, Problem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if c.Monitor.IntervalMinutes < 0 {
+		add("monitor.interval_minutes", "0 이상이어야 합니다 (0이면 기본값 %d분)", DefaultIntervalMinutes)
+	}
+
+	required := map[string]string{
+		"storage.data_dir":      c.Storage.DataDir,
+		"storage.instance_file": c.Storage.InstanceFile,
+		"storage.price_file":    c.Storage.PriceFile,
+	}
+	urls := map[string]string{
+		"nhn_cloud.identity_url": c.NHNCloud.IdentityURL,
+		"nhn_cloud.compute_url":  c.NHNCloud.ComputeURL,
+		"aws.endpoint":           c.AWS.Endpoint,
+		"naver_cloud.endpoint":   c.NaverCloud.Endpoint,
+	}
+	for _, key := range Keys() {
+		if value, ok := required[key]; ok && value == "" {
+			add(key, "값이 비어 있습니다")
+		}
+		if value, ok := urls[key]; ok && value != "" {
+			if parsed, err := url.Parse(value); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				add(key, "http:// 또는 https://로 시작하는 주소가 필요합니다: %q", value)
+			}
+		}
+	}
+
+	for key, regions := range map[string][]string{"aws.regions": c.AWS.Regions, "naver_cloud.regions": c.NaverCloud.Regions} {
+		for _, region := range regions {
+			if strings.TrimSpace(region) == "" {
+				add(key, "빈 리전 이름이 있습니다")
+				break
+			}
+		}
+	}
+
+	// 명령이 파일보다 우선하므로 둘 다 설정되어 있으면 파일은 사용되지 않음
+	sources := [][3]string{
+		{"nhn_cloud.password_file", c.NHNCloud.PasswordFile, c.NHNCloud.PasswordCommand},
+		{"aws.secret_access_key_file", c.AWS.SecretAccessKeyFile, c.AWS.SecretAccessKeyCommand},
+		{"naver_cloud.secret_key_file", c.NaverCloud.SecretKeyFile, c.NaverCloud.SecretKeyCommand},
+	}
+	for _, source := range sources {
+		if source[1] != "" && source[2] != "" {
+			add(source[0], "%s_command와 함께 설정되어 있습니다. 둘 중 하나만 설정해주세요", strings.TrimSuffix(source[0], "_file"))
+		}
+	}
+
+	sortProblems(problems)
+	return problems
+}
+
+// checkFields reports the keys of a config object that are unknown or hold a
+// value of the wrong JSON type
+func checkFields(raw map[string]json.RawMessage, t reflect.Type, prefix string) []Problem {
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []Problem
+	for _, name := range names {
+		key := prefix + name
+		field, ok := structField(reflect.New(t).Elem(), name)
+		if !ok {
+			problems = append(problems, Problem{Key: key, Message: "알 수 없는 설정 키입니다", Discarded: true})
+			continue
+		}
+		if string(raw[name]) == "null" {
+			continue
+		}
+
+		if field.Kind() == reflect.Struct {
+			var section map[string]json.RawMessage
+			if err := json.Unmarshal(raw[name], &section); err != nil {
+				problems = append(problems, Problem{Key: key, Message: "형식이 맞지 않습니다 (객체 필요)", Discarded: true})
+				continue
+			}
+			problems = append(problems, checkFields(section, field.Type(), key+".")...)
+			continue
+		}
+		if err := json.Unmarshal(raw[name], reflect.New(field.Type()).Interface()); err != nil {
+			problems = append(problems, Problem{Key: key, Message: fmt.Sprintf("형식이 맞지 않습니다 (%s 필요): %s", typeName(field.Type()), raw[name]), Discarded: true})
+		}
+	}
+	return problems
+}
+
+// sortProblems orders problems like the keys in the config file, with
+// unknown keys last
+func sortProblems(problems []Problem) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		return keyIndex(problems[i].Key) < keyIndex(problems[j].Key)
+	})
+}
+
+// keyIndex returns the position of key in Keys, past the end for unknown keys
+func keyIndex(key string) int {
+	for i, k := range Keys() {
+		if k == key {
+			return i
+		}
+	}
+	return len(Keys())
+}
@@ -0,0 +1,227 @@
+package configcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"costconfig/config"
+)
+
+// InitCommand returns config init, which creates the config file of the
+// active profile with its own data directory
+func (e *Env) InitCommand() *cobra.Command {
+	e.initCmd = &cobra.Command{
+		Use:   "init",
+		Short: "설정 파일 초기화",
+		Long: `기본 설정 파일을 생성합니다.
+
+--profile이나 환경 변수 COSTCTL_PROFILE로 프로필을 지정하면 ~/.costctl/profiles/<프로필>/config.json에
+생성하고, 데이터 파일도 해당 프로필의 data 디렉토리에 저장합니다.
+costcli와 cost-collect는 같은 설정 파일과 프로필 디렉토리를 사용하므로 어느 쪽에서 생성해도 됩니다.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := &config.Config{
+				NHNCloud: config.NHNCloudConfig{
+					TenantID:    "",
+					Username:    "",
+					Password:    "",
+					Region:      "KR1",
+					IdentityURL: "https://api-identity-infrastructure.nhncloudservice.com",
+				},
+				Monitor: config.MonitorConfig{
+					IntervalMinutes: config.DefaultIntervalMinutes,
+					AutoStart:       false,
+				},
+				Storage: config.StorageConfig{},
+			}
+
+			// 프로필마다 별도의 데이터 디렉토리를 사용하여 수집 데이터가 섞이지 않도록 함
+			profile := config.ActiveProfile(e.Profile)
+			profileDir, err := config.ProfileDir(profile)
+			if err != nil {
+				return err
+			}
+			dataDir := filepath.Join(profileDir, "data")
+
+			cfg.Storage.DataDir = dataDir
+			cfg.Storage.InstanceFile = filepath.Join(dataDir, "instances.json")
+			cfg.Storage.PriceFile = filepath.Join(dataDir, "pricing.json")
+			cfg.Storage.CreditFile = filepath.Join(dataDir, "credits.json")
+
+			if err := cfg.Save(e.ConfigPath); err != nil {
+				return fmt.Errorf("설정 파일 저장 실패: %w", err)
+			}
+			if err := os.MkdirAll(dataDir, 0700); err != nil {
+				return fmt.Errorf("데이터 디렉토리 생성 실패: %w", err)
+			}
+
+			command := e.command(profile)
+			fmt.Printf("설정 파일이 생성되었습니다: %s\n", e.ConfigPath)
+			fmt.Println("NHN Cloud 인증 정보를 설정 파일에 추가해주세요:")
+			fmt.Println("  - tenant_id: NHN Cloud 프로젝트 ID")
+			fmt.Println("  - username: NHN Cloud 사용자명 (이메일)")
+			fmt.Println("  - password: API 비밀번호")
+			fmt.Println("")
+			fmt.Println("설정 방법:")
+			fmt.Printf("  %s config set nhn.tenant_id \"your-tenant-id\"\n", command)
+			fmt.Printf("  %s config set nhn.username \"your-email@example.com\"\n", command)
+			fmt.Printf("  %s config set nhn.password   # 입력한 비밀번호는 화면에 표시되지 않습니다\n", command)
+
+			return nil
+		},
+	}
+	return e.initCmd
+}
+
+// Commands returns the config subcommands both binaries provide: profiles,
+// get, set, unset and validate
+func (e *Env) Commands() []*cobra.Command {
+	profilesCmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "프로필 목록",
+		Long:  `설정 파일이 있는 프로필 목록을 표시합니다. 현재 프로필은 *로 표시합니다.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return e.PrintProfiles()
+		},
+	}
+
+	getCmd := &cobra.Command{
+		Use:   "get [key]",
+		Short: "설정 값 조회",
+		Long: `설정 값을 조회합니다. 키는 설정 파일의 JSON 이름을 점으로 이은 형식입니다 (예: monitor.interval_minutes).
+섹션 이름(예: aws)을 지정하면 섹션 전체를 JSON으로 출력하고, 키를 생략하면 모든 설정 값을 출력합니다.
+목록 값은 쉼표로 구분하여 출력하며, 비밀번호와 비밀 키는 어느 경우에도 가려서 출력합니다.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, _, err := config.ReadConfig(e.ConfigPath)
+			if err != nil {
+				return fmt.Errorf("설정 로딩 실패: %w", err)
+			}
+
+			if len(args) == 1 {
+				value, err := cfg.Get(args[0])
+				if err != nil {
+					return err
+				}
+				fmt.Println(value)
+				return nil
+			}
+
+			for _, key := range config.Keys() {
+				value, err := cfg.Get(key)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("%s = %s\n", key, value)
+			}
+			return nil
+		},
+	}
+
+	setCmd := &cobra.Command{
+		Use:   "set [key] [value]",
+		Short: "설정 값 변경",
+		Long: `설정 값을 변경합니다. 키 형식은 'config get'과 같으며, 값은 설정 항목의 형식(문자열, 정수, true/false)에
+맞아야 합니다. 목록 값(예: aws.regions)은 쉼표로 구분하여 지정합니다. 잘못된 값은 저장하지 않습니다.
+
+비밀번호와 비밀 키(nhn_cloud.password, aws.secret_access_key, aws.session_token, naver_cloud.secret_key)는
+값을 생략하면 표준 입력에서 읽으며, 터미널에서는 입력 내용을 화면에 표시하지 않습니다.
+설정 파일에 평문으로 저장하지 않으려면 nhn_cloud.password_file, nhn_cloud.password_command 또는
+환경 변수 COSTCTL_NHN_PASSWORD 등을 사용하세요.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := config.CanonicalKey(args[0])
+			if err != nil {
+				return err
+			}
+			env, secret := config.IsSecretKey(key)
+
+			var value string
+			switch {
+			case len(args) == 2:
+				value = args[1]
+			case secret:
+				if value, err = ReadSecretValue(key); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("%s 값을 지정해주세요", key)
+			}
+
+			cfg, problems, err := config.ReadConfig(e.ConfigPath)
+			if err != nil {
+				return fmt.Errorf("설정 로딩 실패: %w", err)
+			}
+			if err := cfg.Set(key, value); err != nil {
+				return err
+			}
+			if err := e.SaveConfig(cfg); err != nil {
+				return err
+			}
+			WarnDiscarded(problems, key)
+
+			if secret {
+				fmt.Printf("설정이 변경되었습니다: %s = %s\n", key, config.MaskSecret(value))
+				fmt.Printf("경고: %s가 설정 파일에 평문으로 저장되었습니다. 명령, 파일 또는 환경 변수 %s 사용을 권장합니다\n", key, env)
+			} else {
+				value, _ = cfg.Get(key)
+				fmt.Printf("설정이 변경되었습니다: %s = %s\n", key, value)
+			}
+			return nil
+		},
+	}
+
+	unsetCmd := &cobra.Command{
+		Use:   "unset [key]",
+		Short: "설정 값 삭제",
+		Long:  `설정 값을 기본값(빈 값)으로 되돌립니다. 섹션 이름(예: naver_cloud)을 지정하면 섹션 전체를 삭제합니다.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := config.CanonicalKey(args[0])
+			if err != nil {
+				return err
+			}
+
+			cfg, problems, err := config.ReadConfig(e.ConfigPath)
+			if err != nil {
+				return fmt.Errorf("설정 로딩 실패: %w", err)
+			}
+			if err := cfg.Unset(key); err != nil {
+				return err
+			}
+			if err := e.SaveConfig(cfg); err != nil {
+				return err
+			}
+			WarnDiscarded(problems, key)
+
+			fmt.Printf("설정이 삭제되었습니다: %s\n", key)
+			return nil
+		},
+	}
+
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "설정 파일 검사",
+		Long:  `설정 파일의 알 수 없는 키, 형식이 맞지 않는 값, 허용되지 않는 값을 한 번에 모두 찾아 표시합니다.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.ConfigFilePath(e.ConfigPath)
+			if err != nil {
+				return err
+			}
+			_, problems, err := config.ReadConfig(path)
+			if err != nil {
+				return fmt.Errorf("설정 로딩 실패: %w", err)
+			}
+			if len(problems) > 0 {
+				return &config.ValidationError{Path: path, Problems: problems}
+			}
+
+			fmt.Printf("설정 파일에 문제가 없습니다: %s\n", path)
+			return nil
+		},
+	}
+
+	return []*cobra.Command{profilesCmd, getCmd, setCmd, unsetCmd, validateCmd}
+}
@@ -0,0 +1,72 @@
+// Package configcmd provides the config subcommands shared by costcli and
+// cost-collect. Both binaries read the same config files and profiles, so
+// they manage them with the same commands.
+package configcmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"costconfig/config"
+)
+
+// Env is the config state of the binary the commands are added to
+type Env struct {
+	// 안내 메시지에 표시할 실행 파일 이름
+	Binary string
+	// --config 값. ResolveConfigPath 이후에는 사용할 설정 파일
+	ConfigPath string
+	// --profile 값
+	Profile string
+	// 사용 중인 프로필. --config를 지정하면 ""
+	ActiveProfile string
+
+	initCmd *cobra.Command
+}
+
+// NewEnv returns the config state of the named binary
+func NewEnv(binary string) *Env {
+	return &Env{Binary: binary}
+}
+
+// AddFlags registers --config and --profile on the root command
+func (e *Env) AddFlags(root *cobra.Command) {
+	root.PersistentFlags().StringVarP(&e.ConfigPath, "config", "c", "", "설정 파일 경로")
+	root.PersistentFlags().StringVar(&e.Profile, "profile", "", "사용할 프로필 (기본값: 환경 변수 COSTCTL_PROFILE 또는 default)")
+}
+
+// ResolveConfigPath points ConfigPath at the config file of the active
+// profile unless --config is given
+func (e *Env) ResolveConfigPath(cmd *cobra.Command) error {
+	if e.ConfigPath != "" {
+		if cmd.Flags().Changed("profile") {
+			return fmt.Errorf("--config와 --profile은 함께 사용할 수 없습니다")
+		}
+		return nil
+	}
+
+	e.ActiveProfile = config.ActiveProfile(e.Profile)
+	path, err := config.ProfilePath(e.ActiveProfile)
+	if err != nil {
+		return err
+	}
+	e.ConfigPath = path
+
+	// config init 전에는 새 프로필의 설정 파일이 없음
+	if e.ActiveProfile != config.DefaultProfile && cmd != e.initCmd {
+		if _, err := os.Stat(e.ConfigPath); os.IsNotExist(err) {
+			return fmt.Errorf("프로필 %s의 설정 파일이 없습니다 (%s). '%s config init'으로 생성하세요", e.ActiveProfile, e.ConfigPath, e.command(e.ActiveProfile))
+		}
+	}
+	return nil
+}
+
+// command returns how to invoke the binary for a profile in messages
+func (e *Env) command(profile string) string {
+	if profile == "" || profile == config.DefaultProfile {
+		return e.Binary
+	}
+	return e.Binary + " --profile " + profile
+}
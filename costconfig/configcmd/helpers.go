@@ -0,0 +1,111 @@
+package configcmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"costconfig/config"
+)
+
+// PrintProfiles lists the profiles with a config file, marking the active one
+func (e *Env) PrintProfiles() error {
+	profiles, err := config.Profiles()
+	if err != nil {
+		return err
+	}
+	if len(profiles) == 0 {
+		fmt.Printf("설정된 프로필이 없습니다. '%s config init --profile <이름>'으로 생성하세요\n", e.Binary)
+		return nil
+	}
+
+	for _, name := range profiles {
+		marker := " "
+		if name == e.ActiveProfile {
+			marker = "*"
+		}
+		path, err := config.ProfilePath(name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s (%s)\n", marker, name, path)
+	}
+	return nil
+}
+
+// SaveConfig writes cfg back to the config file it was read from
+func (e *Env) SaveConfig(cfg *config.Config) error {
+	path, err := config.ConfigFilePath(e.ConfigPath)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Save(path); err != nil {
+		return fmt.Errorf("설정 저장 실패: %w", err)
+	}
+	return nil
+}
+
+// WarnDiscarded lists the values that could not be read and so were not
+// written back when the config was saved
+func WarnDiscarded(problems []config.Problem, key string) {
+	for _, problem := range problems {
+		if problem.Discarded && problem.Key != key && !strings.HasPrefix(problem.Key, key+".") {
+			fmt.Fprintf(os.Stderr, "경고: %s 값을 읽을 수 없어 설정 파일에서 제외되었습니다: %s\n", problem.Key, problem.Message)
+		}
+	}
+}
+
+// ReadSecretValue reads a secret from standard input, turning off terminal
+// echo while it is typed
+func ReadSecretValue(key string) (string, error) {
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprintf(os.Stderr, "%s 입력: ", key)
+		if stty("-echo") == nil {
+			defer func() {
+				stty("echo")
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("%s 값 읽기 실패: %w", key, err)
+	}
+	value := strings.TrimRight(line, "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("%s 값이 비어 있습니다", key)
+	}
+	return value, nil
+}
+
+// stty changes the terminal attached to standard input
+func stty(arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+// DescribeValue shows a credential, noting when an environment variable
+// overrides it
+func DescribeValue(value, env string) string {
+	if override := os.Getenv(env); override != "" {
+		return override + " (환경 변수 " + env + ")"
+	}
+	return value
+}
+
+// DescribeSecret shows where a secret is read from, or its masked value when
+// it is stored in the config file
+func DescribeSecret(value, env, file, command string) string {
+	if source := config.SecretSource(env, file, command); source != "" {
+		return source
+	}
+	if value != "" {
+		return config.MaskSecret(value) + " (설정 파일에 평문 저장)"
+	}
+	return config.MaskSecret(value)
+}